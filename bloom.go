@@ -0,0 +1,91 @@
+package collection
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/trviph/collection/internal"
+)
+
+// A [BloomFilter] is a probabilistic set of hashes, using a fixed amount of memory.
+// It can tell for sure that a hash has not been added,
+// but may falsely tell that a hash has been added because of collisions between hashes.
+// All operation on [BloomFilter] is thread-safe,
+// because it only allow one goroutine at a time to access it data.
+//
+// [Bloom filter]: https://en.wikipedia.org/wiki/Bloom_filter
+type BloomFilter struct {
+	mu   sync.RWMutex
+	bits []uint64
+	// The number of bits, and the number of bits set for each hash.
+	m, k uint64
+}
+
+// Interface guard
+var _ internal.BloomFilter = (*BloomFilter)(nil)
+
+// [NewBloomFilter] creates a new [BloomFilter] sized to hold n hashes,
+// with a probability of false positive of about p once n hashes have been added.
+// This will return an error if n is less than 1 or p is not between 0 and 1 exclusively.
+func NewBloomFilter(n int, p float64) (*BloomFilter, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("failed to create bloom filter; cause by invalid specified size of %d", n)
+	}
+	if p <= 0 || p >= 1 {
+		return nil, fmt.Errorf("failed to create bloom filter; cause by invalid specified probability of %v", p)
+	}
+
+	// The optimal number of bits and hashes, see https://en.wikipedia.org/wiki/Bloom_filter#Optimal_number_of_hash_functions
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	m, k = max(m, 64), max(k, 1)
+	return &BloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}, nil
+}
+
+// Like [NewBloomFilter] but will panic on error.
+func MustNewBloomFilter(n int, p float64) *BloomFilter {
+	return Must(func() (*BloomFilter, error) {
+		return NewBloomFilter(n, p)
+	})
+}
+
+// Add a hash to the filter.
+func (f *BloomFilter) Add(hash uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	h2 := rehash(hash)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (hash + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// Contains returns false if the hash has never been added to the filter,
+// and true if it has probably been added.
+func (f *BloomFilter) Contains(hash uint64) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	h2 := rehash(hash)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (hash + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset removes all hashes from the filter.
+func (f *BloomFilter) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	clear(f.bits)
+}
@@ -0,0 +1,45 @@
+package collection_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/trviph/collection"
+)
+
+func TestBloomFilterRace(t *testing.T) {
+	var wg sync.WaitGroup
+	filter := collection.MustNewBloomFilter(randint(10, 1000), 0.01)
+	functions := []func(){
+		// Add to the filter
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				filter.Add(rand.Uint64())
+			}
+		},
+
+		// Check the filter
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = filter.Contains(rand.Uint64())
+			}
+		},
+
+		// Reset the filter
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 100); i++ {
+				filter.Reset()
+			}
+		},
+	}
+
+	wg.Add(len(functions))
+	for _, f := range functions {
+		go f()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,67 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/trviph/collection"
+)
+
+func TestNewBloomFilter(t *testing.T) {
+	_, err := collection.NewBloomFilter(0, 0.01)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewBloomFilter", "error", err)
+	}
+	_, err = collection.NewBloomFilter(100, 0)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewBloomFilter", "error", err)
+	}
+	_, err = collection.NewBloomFilter(100, 1)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewBloomFilter", "error", err)
+	}
+	_, err = collection.NewBloomFilter(100, 0.01)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestNewBloomFilter", "nil error", err)
+	}
+}
+
+func TestMustNewBloomFilter(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewBloomFilter", "panic", r)
+		}
+	}()
+	_ = collection.MustNewBloomFilter(0, 0.01)
+}
+
+func TestBloomFilter(t *testing.T) {
+	filter := collection.MustNewBloomFilter(1000, 0.01)
+	for hash := uint64(0); hash < 1000; hash++ {
+		filter.Add(hash * 0x9e3779b97f4a7c15)
+	}
+
+	// There is no false negative
+	for hash := uint64(0); hash < 1000; hash++ {
+		if !filter.Contains(hash * 0x9e3779b97f4a7c15) {
+			t.Errorf(testFailedMsg, "TestBloomFilter", true, false)
+		}
+	}
+
+	// False positives should be around 1%, allow some slack
+	falsePositives := 0
+	for hash := uint64(1000); hash < 11000; hash++ {
+		if filter.Contains(hash * 0x9e3779b97f4a7c15) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 300 {
+		t.Errorf(testFailedMsg, "TestBloomFilter", "at most 300 false positives", falsePositives)
+	}
+
+	filter.Reset()
+	for hash := uint64(0); hash < 1000; hash++ {
+		if filter.Contains(hash * 0x9e3779b97f4a7c15) {
+			t.Errorf(testFailedMsg, "TestBloomFilter", false, true)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package cache
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/internal"
+)
+
+// A cache using the Adaptive Replacement Cache eviction policy.
+// It balances between recency and frequency, by splitting entries into two LRU lists,
+// t1 for entries used once recently and t2 for entries used at least twice recently.
+// Keys recently evicted from t1 and t2 are remembered in the ghost lists b1 and b2,
+// a hit on a ghost list adapts the target size of t1 toward the list that would have kept the key.
+//
+// [Adaptive Replacement Cache (ARC)]: https://en.wikipedia.org/wiki/Adaptive_replacement_cache
+// [ARC: A Self-Tuning, Low Overhead Replacement Cache]: https://www.usenix.org/conference/fast-03/arc-self-tuning-low-overhead-replacement-cache
+type ARC[K comparable, T any] struct {
+	core[K, T]
+
+	// Entries that have been used only once recently.
+	t1 lruList[K, T]
+	// Entries that have been used at least twice recently.
+	t2 lruList[K, T]
+	// Keys recently evicted from t1, their entries hold no value.
+	b1 lruList[K, T]
+	// Keys recently evicted from t2, their entries hold no value.
+	b2 lruList[K, T]
+
+	// The target cost of t1, adapted on every hit of the ghost lists.
+	p int64
+
+	// Where the key about to be added was found, set by miss and used by evict and add.
+	missInB1, missInB2 bool
+	// If the next eviction should drop the least recently used entry of t1 without remembering it.
+	discardT1 bool
+}
+
+var _ internal.Cache[int, any] = (*ARC[int, any])(nil)
+
+// [NewARC] creates a new cache with [ARC] eviction policy.
+// It accepts cap as the first argument, specifying the maximum capacity of the cache,
+// followed by any [Option] to configure the cache.
+// The cache also remembers up to cap keys of evicted entries, without their values.
+// It will return an error if cap is less than 1 or an option is invalid.
+func NewARC[K comparable, T any](cap int, opts ...Option) (*ARC[K, T], error) {
+	if cap < 1 {
+		return nil, fmt.Errorf("failed to create arc; cause by invalid specified capacity")
+	}
+	return newARC[K, T](int64(cap), nil, opts)
+}
+
+// Like [NewARC] but will panic on error.
+func MustNewARC[K comparable, T any](cap int, opts ...Option) *ARC[K, T] {
+	return collection.Must(
+		func() (*ARC[K, T], error) {
+			return NewARC[K, T](cap, opts...)
+		},
+	)
+}
+
+// [NewARCWithCost] is like [NewARC], but the capacity of the cache is measured by the cost of entries,
+// instead of the number of entries. It accepts maxCost as the first argument, specifying the maximum total cost of the cache,
+// and cost computing the cost of an entry, which should not be negative.
+// It will return an error if maxCost is less than 1, cost is nil or an option is invalid.
+func NewARCWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) (*ARC[K, T], error) {
+	if err := checkCost(maxCost, cost); err != nil {
+		return nil, fmt.Errorf("failed to create arc; cause by %w", err)
+	}
+	return newARC(maxCost, cost, opts)
+}
+
+// Like [NewARCWithCost] but will panic on error.
+func MustNewARCWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) *ARC[K, T] {
+	return collection.Must(
+		func() (*ARC[K, T], error) {
+			return NewARCWithCost(maxCost, cost, opts...)
+		},
+	)
+}
+
+func newARC[K comparable, T any](cap int64, cost func(key K, value T) int64, opts []Option) (*ARC[K, T], error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create arc; cause by %w", err)
+	}
+
+	c := &ARC[K, T]{
+		t1: newLRUList[K, T](),
+		t2: newLRUList[K, T](),
+		b1: newLRUList[K, T](),
+		b2: newLRUList[K, T](),
+	}
+	c.init(cap, cost, c, o)
+	return c, nil
+}
+
+func (c *ARC[K, T]) lookup(key K) (*entry[K, T], bool) {
+	if e, ok := c.t1.lookup(key); ok {
+		return e, true
+	}
+	return c.t2.lookup(key)
+}
+
+func (c *ARC[K, T]) touch(e *entry[K, T]) {
+	// A hit on t1 means the entry is used twice, so it is moved to t2
+	if _, ok := c.t1.lookup(e.key); ok {
+		c.t1.remove(e)
+		c.t2.pushFront(e)
+		return
+	}
+	c.t2.moveToFront(e)
+}
+
+func (c *ARC[K, T]) miss(key K) {
+	ghostB1, inB1 := c.b1.lookup(key)
+	ghostB2, inB2 := c.b2.lookup(key)
+	c.missInB1, c.missInB2 = inB1, inB2
+	c.discardT1 = false
+
+	switch {
+	case c.missInB1:
+		// The key would have been kept if t1 were larger, so grow its target size
+		delta := int64(max(c.b2.len()/c.b1.len(), 1)) * max(ghostB1.cost, 1)
+		c.p = min(c.p+delta, c.cap)
+	case c.missInB2:
+		// The key would have been kept if t2 were larger, so shrink the target size of t1
+		delta := int64(max(c.b1.len()/c.b2.len(), 1)) * max(ghostB2.cost, 1)
+		c.p = max(c.p-delta, 0)
+	case c.t1.cost()+c.b1.cost() >= c.cap:
+		// Keep t1 and b1 together within cap, either by forgetting keys of b1
+		// or by dropping the least recently used entry of t1 if b1 is empty.
+		if c.t1.cost() < c.cap {
+			for c.b1.len() > 0 && c.t1.cost()+c.b1.cost() >= c.cap {
+				_ = c.b1.popBack()
+			}
+		} else {
+			c.discardT1 = true
+		}
+	case c.t1.cost()+c.t2.cost()+c.b1.cost()+c.b2.cost() >= 2*c.cap:
+		// Keep all lists together within twice the cap
+		for c.b2.len() > 0 && c.t1.cost()+c.t2.cost()+c.b1.cost()+c.b2.cost() >= 2*c.cap {
+			_ = c.b2.popBack()
+		}
+	}
+}
+
+func (c *ARC[K, T]) add(e *entry[K, T]) {
+	// A key found in a ghost list has been used before, so it goes straight to t2.
+	// The ghost is removed by its own entry, as its cost may differ from the cost of the new entry.
+	switch {
+	case c.missInB1:
+		if ghost, ok := c.b1.lookup(e.key); ok {
+			c.b1.remove(ghost)
+		}
+		c.t2.pushFront(e)
+	case c.missInB2:
+		if ghost, ok := c.b2.lookup(e.key); ok {
+			c.b2.remove(ghost)
+		}
+		c.t2.pushFront(e)
+	default:
+		c.t1.pushFront(e)
+	}
+	c.missInB1, c.missInB2 = false, false
+
+	// Dropping entries of different costs may remember more keys than the lists can hold,
+	// so forget the oldest keys until t1 and b1 are within cap, and all lists are within twice the cap.
+	for c.b1.len() > 0 && c.t1.cost()+c.b1.cost() > c.cap {
+		_ = c.b1.popBack()
+	}
+	for c.b2.len() > 0 && c.t1.cost()+c.t2.cost()+c.b1.cost()+c.b2.cost() > 2*c.cap {
+		_ = c.b2.popBack()
+	}
+}
+
+func (c *ARC[K, T]) remove(e *entry[K, T]) {
+	if _, ok := c.t1.lookup(e.key); ok {
+		c.t1.remove(e)
+		return
+	}
+	c.t2.remove(e)
+}
+
+// Drop the least recently used entry of either t1 or t2, depending on the target size of t1.
+// This is the REPLACE subroutine of ARC.
+func (c *ARC[K, T]) evict() *entry[K, T] {
+	if c.discardT1 {
+		c.discardT1 = false
+		if e := c.t1.popBack(); e != nil {
+			return e
+		}
+	}
+
+	t1Cost := c.t1.cost()
+	if c.t1.len() > 0 && (t1Cost > c.p || (t1Cost == c.p && c.missInB2) || c.t2.len() == 0) {
+		e := c.t1.popBack()
+		c.b1.pushFront(&entry[K, T]{key: e.key, cost: e.cost})
+		return e
+	}
+
+	e := c.t2.popBack()
+	if e == nil {
+		// This should never happend
+		panic(
+			fmt.Errorf("something went very wrong; cannot drop ARC entry of cache with capacity of %d, entries length %d", c.cap, c.len()),
+		)
+	}
+	c.b2.pushFront(&entry[K, T]{key: e.key, cost: e.cost})
+	return e
+}
+
+func (c *ARC[K, T]) len() int {
+	return c.t1.len() + c.t2.len()
+}
+
+func (c *ARC[K, T]) clear() {
+	c.t1.clear()
+	c.t2.clear()
+	c.b1.clear()
+	c.b2.clear()
+	c.p = 0
+}
+
+// Iterate over entries of t2 then t1, each from the most recently used to the least recently used.
+func (c *ARC[K, T]) entries() iter.Seq[*entry[K, T]] {
+	return func(yield func(*entry[K, T]) bool) {
+		for e := range c.t2.entries() {
+			if !yield(e) {
+				return
+			}
+		}
+		for e := range c.t1.entries() {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package cache_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/cache"
+)
+
+func TestNewARC(t *testing.T) {
+	_, err := cache.NewARC[int, int](0)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewARC", "error", err)
+	}
+	_, err = cache.NewARC[int, int](1)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestNewARC", "nil error", err)
+	}
+}
+
+func TestMustNewARC(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewARC", "panic", r)
+		}
+	}()
+	_ = cache.MustNewARC[int, any](-1)
+}
+
+func TestARC(t *testing.T) {
+	arc := cache.MustNewARC[int, string](2)
+
+	// Should get is empty error
+	if _, err := arc.Get(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestARC", collection.ErrIsEmpty, err)
+	}
+
+	// A is used twice, so it is kept over B which is only used once
+	arc.Put(1, "A")
+	arc.Put(2, "B")
+	_, _ = arc.Get(1)
+	arc.Put(3, "C")
+	if _, err := arc.Get(2); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestARC", collection.ErrNotFound, err)
+	}
+	if val, err := arc.Get(1); err != nil {
+		t.Errorf(testFailedMsg, "TestARC", "nil error", err)
+	} else if val != "A" {
+		t.Errorf(testFailedMsg, "TestARC", "A", val)
+	}
+
+	// Deleting and clearing should keep the cache usable
+	if !arc.Delete(1) {
+		t.Errorf(testFailedMsg, "TestARC", true, false)
+	}
+	arc.Put(4, "D")
+	arc.Put(5, "E")
+	if arc.Len() != 2 {
+		t.Errorf(testFailedMsg, "TestARC", 2, arc.Len())
+	}
+	arc.Clear()
+	if arc.Len() != 0 {
+		t.Errorf(testFailedMsg, "TestARC", 0, arc.Len())
+	}
+	arc.Put(2, "B")
+	if val, err := arc.Get(2); err != nil {
+		t.Errorf(testFailedMsg, "TestARC", "nil error", err)
+	} else if val != "B" {
+		t.Errorf(testFailedMsg, "TestARC", "B", val)
+	}
+}
+
+// These are short synthetic traces written for the test, not traces published with the paper.
+// Their expected evictions are computed by hand by following
+// the pseudo-code of ARC in Figure 4 of the paper by Megiddo and Modha,
+// where a request is a Get followed by a Put if the key is missing.
+func TestARCTraces(t *testing.T) {
+	traces := []struct {
+		name    string
+		cap     int
+		trace   []int
+		evicted []int
+		kept    []int
+	}{
+		{
+			// Ghost hits on b2 favor frequency, and the scan of 7, 8, 9, 10 does not flush 3 and 4
+			name:    "recency then frequency",
+			cap:     4,
+			trace:   []int{1, 2, 3, 4, 1, 2, 5, 6, 1, 2, 7, 8, 3, 4, 1, 2, 9, 3, 4, 10},
+			evicted: []int{3, 4, 5, 6, 7, 8, 3, 4, 1, 2},
+			kept:    []int{3, 4, 9, 10},
+		},
+		{
+			// Entries used twice survive a scan of entries used once
+			name:    "scan",
+			cap:     3,
+			trace:   []int{1, 1, 2, 2, 3, 4, 5, 6, 1, 2, 7, 8, 1, 2},
+			evicted: []int{3, 4, 5, 6, 7},
+			kept:    []int{1, 2, 8},
+		},
+		{
+			// Ghost hits on b1 grow t1 and ghost hits on b2 shrink it back
+			name:    "adapt",
+			cap:     4,
+			trace:   []int{1, 2, 1, 2, 3, 4, 5, 6, 3, 4, 7, 3, 4, 8, 1, 2},
+			evicted: []int{3, 4, 5, 1, 2, 3, 6, 7},
+			kept:    []int{1, 2, 4, 8},
+		},
+	}
+
+	for _, tc := range traces {
+		arc := cache.MustNewARC[int, int](tc.cap)
+		evicted := make([]int, 0)
+		arc.OnEvict(func(key, value int, reason cache.EvictReason) {
+			if reason == cache.EvictCapacity {
+				evicted = append(evicted, key)
+			}
+		})
+
+		for _, key := range tc.trace {
+			if _, err := arc.Get(key); err != nil {
+				arc.Put(key, key)
+			}
+		}
+
+		if !slices.Equal(evicted, tc.evicted) {
+			t.Errorf(testFailedMsg, "TestARCTraces "+tc.name, tc.evicted, evicted)
+		}
+		if arc.Len() != len(tc.kept) {
+			t.Errorf(testFailedMsg, "TestARCTraces "+tc.name, len(tc.kept), arc.Len())
+		}
+		for _, key := range tc.kept {
+			if !arc.Contains(key) {
+				t.Errorf(testFailedMsg, "TestARCTraces "+tc.name, true, false)
+			}
+		}
+	}
+}
+
+func TestARCGhostCost(t *testing.T) {
+	// Ghost lists remember keys up to a total cost of twice the capacity
+	arc := cache.MustNewARCWithCost(40, func(key int, value string) int64 {
+		return int64(len(value))
+	})
+
+	// Keys are put again while remembered in b1 or b2, but with a different cost than their ghosts
+	for i := range 1000 {
+		key := i % 30
+		if i/30%3 == 2 {
+			_, _ = arc.Get(key)
+		}
+		arc.Put(key, strings.Repeat("A", 1+i/30%2*9))
+
+		length, cost := cache.ARCGhosts(arc)
+		if cost < 0 || cost > 80 {
+			t.Fatalf(testFailedMsg, "TestARCGhostCost", "cost within [0, 80]", cost)
+		}
+		if int64(length) > cost {
+			t.Fatalf(testFailedMsg, "TestARCGhostCost", "length of at most the cost", length)
+		}
+	}
+}
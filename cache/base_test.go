@@ -1,9 +1,35 @@
 package cache_test
 
-import "math/rand"
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
 
 const testFailedMsg string = "%s failed; want %v but got %v"
 
 func randint(atleast, atmost int) int {
 	return rand.Intn(atmost-atleast) + atleast
 }
+
+// A clock that only moves when told to, so tests with TTL do not need to sleep.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// Cache is implemented by every cache of this package, whatever its eviction policy.
+// It can be used to write code that does not depend on a policy, such as the factory of [NewSharded].
+type Cache[K comparable, T any] interface {
+	Put(key K, value T)
+	PutWithTTL(key K, value T, ttl time.Duration)
+	TryPut(key K, value T) error
+	TryPutWithTTL(key K, value T, ttl time.Duration) error
+	Get(key K) (T, error)
+	Peek(key K) (T, error)
+	GetOrLoad(ctx context.Context, key K, loader func(ctx context.Context, key K) (T, error)) (T, error)
+	Delete(key K) bool
+	Contains(key K) bool
+	Len() int
+	Cap() int
+	Cost() int64
+	Clear()
+	Close() error
+	OnEvict(hook func(key K, value T, reason EvictReason))
+	Stats() Stats
+	ResetStats()
+	All() iter.Seq2[K, T]
+	Backward() iter.Seq2[K, T]
+	Keys() iter.Seq[K]
+	Values() iter.Seq[T]
+}
+
+var (
+	_ Cache[int, any] = (*LRU[int, any])(nil)
+	_ Cache[int, any] = (*MRU[int, any])(nil)
+	_ Cache[int, any] = (*LFU[int, any])(nil)
+	_ Cache[int, any] = (*ARC[int, any])(nil)
+	_ Cache[int, any] = (*TwoQueue[int, any])(nil)
+	_ Cache[int, any] = (*SLRU[int, any])(nil)
+	_ Cache[int, any] = (*TinyLFU[int, any])(nil)
+	_ Cache[int, any] = (*Sharded[int, any])(nil)
+	_ Cache[int, any] = (*Tiered[int, any])(nil)
+)
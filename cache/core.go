@@ -0,0 +1,405 @@
+package cache
+
+import (
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/trviph/collection"
+)
+
+// A policy decides how entries are kept and which entry to drop when the cache is full.
+// Its methods are only called by [core] while holding the cache mutex,
+// so a policy does not need to handle synchronization by itself.
+type policy[K comparable, T any] interface {
+	// Look up the entry associated with the key, without marking it as used.
+	lookup(key K) (*entry[K, T], bool)
+	// Mark an entry of the policy as used.
+	touch(e *entry[K, T])
+	// Called when a key that does not exist in the policy is about to be added,
+	// before making room for it.
+	miss(key K)
+	// Add a new entry, its key must not already exist in the policy.
+	add(e *entry[K, T])
+	// Remove an entry of the policy.
+	remove(e *entry[K, T])
+	// Choose an entry to drop, remove it from the policy and return it.
+	evict() *entry[K, T]
+	// The number of entries in the policy.
+	len() int
+	// Remove all entries of the policy.
+	clear()
+	// Iterate over all entries of the policy.
+	entries() iter.Seq[*entry[K, T]]
+}
+
+// The state and behaviours shared by every cache, while the eviction is delegated to a [policy].
+// Caches embed core, so they are thread-safe,
+// because it only allow one goroutine at a time to access the cache data.
+type core[K comparable, T any] struct {
+	mu     sync.Mutex
+	policy policy[K, T]
+
+	// The maximum total cost of entries, which is the maximum number of entries unless a cost function is given.
+	cap int64
+	// The total cost of entries in the cache.
+	cost int64
+	// The number of times entries have been put or used, it orders entries by recency.
+	uses uint64
+	// Compute the cost of an entry, nil means every entry costs 1.
+	costOf func(key K, value T) int64
+
+	// Default time-to-live of entries, zero means entries never expire.
+	ttl time.Duration
+	// Where the current time come from, it is used to check if entries are expired.
+	now func() time.Time
+	// Remove expired entries in the background, nil if not enabled.
+	janitor *janitor
+
+	// Loads of missing keys that are in progress, by GetOrLoad.
+	loads map[K]*load[T]
+	// Errors of failed loads, only kept if negativeTTL is greater than zero.
+	failures    map[K]*failure
+	negativeTTL time.Duration
+
+	// Encodes and decodes snapshots of the cache.
+	codec Codec
+
+	// Counters of the cache, they can be read without holding the mutex.
+	stats stats
+
+	// Called for every entry removed from the cache, nil if not set.
+	onEvict func(key K, value T, reason EvictReason)
+	// Called for every entry dropped by the eviction policy, to write it to a lower tier, nil if not set.
+	spill func(key K, value T, expiresAt time.Time)
+	// Entries removed while holding the mutex, the hooks are run on them after unlocking.
+	evicted []eviction[K, T]
+}
+
+func (c *core[K, T]) init(cap int64, costOf func(key K, value T) int64, policy policy[K, T], o *options) {
+	c.cap = cap
+	c.costOf = costOf
+	c.policy = policy
+	c.ttl = o.ttl
+	c.now = o.now
+	c.loads = make(map[K]*load[T])
+	c.failures = make(map[K]*failure)
+	c.negativeTTL = o.negativeTTL
+	c.stats.recorder = o.statsRecorder
+	c.codec = o.codec
+	if o.janitorInterval > 0 {
+		c.janitor = startJanitor(o.janitorInterval, c.removeExpired)
+	}
+}
+
+// Put a new value with an associated key into the cache.
+// This will update the value if the key already exist.
+// The entry expires after the default TTL given by [WithTTL], if there is any.
+// This marks the key as used.
+//
+// An entry costing more than the capacity of the cache is not put, see TryPut.
+func (c *core[K, T]) Put(key K, value T) {
+	_ = c.TryPutWithTTL(key, value, c.ttl)
+}
+
+// PutWithTTL is like Put, but the entry expires after the given ttl has passed.
+// A ttl less than or equal to zero means the entry never expires.
+func (c *core[K, T]) PutWithTTL(key K, value T, ttl time.Duration) {
+	_ = c.TryPutWithTTL(key, value, ttl)
+}
+
+// TryPut is like Put, but returns an [*OversizedError] if the cost of the entry
+// is greater than the capacity of the cache, in which case the entry is not put.
+// If the key already exists, its entry is removed anyway so the old value is not returned by Get.
+//
+// Updating an entry with a different cost puts it again as a new entry, so the policy may forget how it was used.
+func (c *core[K, T]) TryPut(key K, value T) error {
+	return c.TryPutWithTTL(key, value, c.ttl)
+}
+
+// TryPutWithTTL is like TryPut, but the entry expires after the given ttl has passed.
+// A ttl less than or equal to zero means the entry never expires.
+func (c *core[K, T]) TryPutWithTTL(key K, value T, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.put(key, value, ttl)
+}
+
+// Put an entry while holding the cache mutex.
+func (c *core[K, T]) put(key K, value T, ttl time.Duration) error {
+	// A value is known for the key, so a failed load of it is no longer relevant
+	delete(c.failures, key)
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+	cost := int64(1)
+	if c.costOf != nil {
+		cost = max(c.costOf(key, value), 0)
+	}
+
+	e, ok := c.policy.lookup(key)
+	if cost > c.cap {
+		if ok {
+			c.removeEntry(e)
+			c.recordEviction(e, EvictReplaced)
+		}
+		return &OversizedError{Cost: cost, MaxCost: c.cap}
+	}
+
+	if ok {
+		c.recordEviction(e, EvictReplaced)
+		// If the cost is the same, update the entry in place
+		if e.cost == cost {
+			e.value = value
+			e.expiresAt = expiresAt
+			c.markUsed(e)
+			c.stats.recordPut()
+			return nil
+		}
+		// Else put it again as a new entry, as the policy may need to make room for its new cost
+		c.removeEntry(e)
+	}
+
+	c.policy.miss(key)
+	c.makeRoom(cost)
+	e = &entry[K, T]{key: key, value: value, expiresAt: expiresAt, cost: cost, used: c.uses}
+	c.uses++
+	c.policy.add(e)
+	c.cost += cost
+	c.stats.recordPut()
+	return nil
+}
+
+// Mark an entry as used, for the policy and for the order of iteration.
+func (c *core[K, T]) markUsed(e *entry[K, T]) {
+	c.policy.touch(e)
+	e.used = c.uses
+	c.uses++
+}
+
+// Make room for a new entry with the given cost by letting the policy drop entries.
+func (c *core[K, T]) makeRoom(cost int64) {
+	for c.cost+cost > c.cap {
+		e := c.policy.evict()
+		c.cost -= e.cost
+		c.recordEviction(e, EvictCapacity)
+	}
+}
+
+// Remove an entry from the policy and release its cost.
+func (c *core[K, T]) removeEntry(e *entry[K, T]) {
+	c.policy.remove(e)
+	c.cost -= e.cost
+}
+
+// Get the value associated with the given key argument.
+// Get will return [collection.ErrNotFound] if there is no such key or the entry is expired,
+// or [collection.ErrIsEmpty] if the cache is empty.
+// This marks the key as used.
+func (c *core[K, T]) Get(key K) (T, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, err := c.find(key)
+	if err != nil {
+		c.stats.recordMiss()
+		var zeroValue T
+		return zeroValue, err
+	}
+	c.stats.recordHit()
+	c.markUsed(e)
+	return e.value, nil
+}
+
+// Peek at the value associated with the given key argument,
+// like Get but does not mark the key as used.
+func (c *core[K, T]) Peek(key K) (T, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, err := c.find(key)
+	if err != nil {
+		var zeroValue T
+		return zeroValue, err
+	}
+	return e.value, nil
+}
+
+// Look up a live entry, expired entries are removed and treated as missing.
+func (c *core[K, T]) find(key K) (*entry[K, T], error) {
+	if c.policy.len() == 0 {
+		return nil, collection.ErrIsEmpty
+	}
+	e, ok := c.policy.lookup(key)
+	if !ok {
+		return nil, collection.ErrNotFound
+	}
+	if e.expired(c.now()) {
+		c.removeEntry(e)
+		c.recordEviction(e, EvictExpired)
+		return nil, collection.ErrNotFound
+	}
+	return e, nil
+}
+
+// Delete removes the entry associated with the given key from the cache.
+// It returns true if the key existed and was not expired, false otherwise.
+func (c *core[K, T]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	delete(c.failures, key)
+	c.discardLoad(key)
+	e, ok := c.policy.lookup(key)
+	if !ok {
+		return false
+	}
+	c.removeEntry(e)
+	c.recordEviction(e, EvictDeleted)
+	return !e.expired(c.now())
+}
+
+// Contains reports whether the key is in the cache and not expired,
+// without marking the key as used.
+func (c *core[K, T]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	_, err := c.find(key)
+	return err == nil
+}
+
+// Len returns the number of entries currently in the cache.
+// This may count expired entries that have not been removed yet.
+func (c *core[K, T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.policy.len()
+}
+
+// Cap returns the maximum number of entries the cache can hold,
+// or the maximum total cost of entries if the cache is created with a cost function.
+func (c *core[K, T]) Cap() int {
+	return int(c.cap)
+}
+
+// Cost returns the total cost of entries currently in the cache,
+// which is the number of entries unless the cache is created with a cost function.
+// This may count expired entries that have not been removed yet.
+func (c *core[K, T]) Cost() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cost
+}
+
+// Clear removes all entries from the cache.
+// The eviction hook is called for each of them with [EvictDeleted] as reason.
+// The values of the keys being loaded by GetOrLoad are not put once loaded.
+func (c *core[K, T]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for e := range c.policy.entries() {
+		c.recordEviction(e, EvictDeleted)
+	}
+	c.policy.clear()
+	c.cost = 0
+	clear(c.failures)
+	for key := range c.loads {
+		c.discardLoad(key)
+	}
+}
+
+// Close stops the janitor of the cache, if there is any.
+// The cache is still usable after Close, but expired entries are no longer removed in the background.
+// Close always returns a nil error, it is safe to call more than once.
+func (c *core[K, T]) Close() error {
+	if c.janitor != nil {
+		c.janitor.close()
+	}
+	return nil
+}
+
+// Remove every expired entries from the cache, this is run by the janitor.
+func (c *core[K, T]) removeExpired() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.now()
+	expired := make([]*entry[K, T], 0)
+	for e := range c.policy.entries() {
+		if e.expired(now) {
+			expired = append(expired, e)
+		}
+	}
+	for _, e := range expired {
+		c.removeEntry(e)
+		c.recordEviction(e, EvictExpired)
+	}
+	for key, f := range c.failures {
+		if !now.Before(f.expiresAt) {
+			delete(c.failures, key)
+		}
+	}
+}
+
+// Stats returns a snapshot of the counters of the cache.
+// The counters are read one by one without holding the cache mutex,
+// so they may not reflect any single moment while the cache is in use.
+func (c *core[K, T]) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// ResetStats sets all counters of the cache back to zero.
+// The [StatsRecorder] given by [WithStatsRecorder], if there is any, is not affected.
+func (c *core[K, T]) ResetStats() {
+	c.stats.reset()
+}
+
+// OnEvict sets a hook that is called for every entry removed from the cache,
+// whether it is dropped by the eviction policy, deleted, expired or overwritten by a Put.
+// Setting the hook again replaces the previous one, a nil hook disables it.
+//
+// The hook is called after the cache mutex is released, so it may call back into the cache.
+// It runs on the goroutine that caused the removal, so it may be called concurrently.
+func (c *core[K, T]) OnEvict(hook func(key K, value T, reason EvictReason)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onEvict = hook
+}
+
+// Count a removed entry, and keep track of it so the eviction hook can be called on it later.
+// An expired entry is always reported as [EvictExpired], whatever the cause of its removal.
+func (c *core[K, T]) recordEviction(e *entry[K, T], reason EvictReason) {
+	if reason != EvictExpired && e.expired(c.now()) {
+		reason = EvictExpired
+	}
+	c.stats.recordEviction(reason)
+	if c.onEvict == nil && (c.spill == nil || reason != EvictCapacity) {
+		return
+	}
+	c.evicted = append(c.evicted, eviction[K, T]{key: e.key, value: e.value, expiresAt: e.expiresAt, reason: reason})
+}
+
+// Unlock the cache mutex, then call the eviction hooks on entries removed while holding it.
+// This should be used instead of c.mu.Unlock by methods that may remove entries.
+func (c *core[K, T]) unlock() {
+	evicted, onEvict, spill := c.evicted, c.onEvict, c.spill
+	c.evicted = nil
+	c.mu.Unlock()
+
+	for _, ev := range evicted {
+		if spill != nil && ev.reason == EvictCapacity {
+			spill(ev.key, ev.value, ev.expiresAt)
+		}
+		if onEvict != nil {
+			onEvict(ev.key, ev.value, ev.reason)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/cache"
+)
+
+// The methods every cache with int keys provides, used to run the same tests against every policy.
+type testCache[T any] interface {
+	Put(key int, value T)
+	PutWithTTL(key int, value T, ttl time.Duration)
+	TryPut(key int, value T) error
+	TryPutWithTTL(key int, value T, ttl time.Duration) error
+	Get(key int) (T, error)
+	Peek(key int) (T, error)
+	Delete(key int) bool
+	Contains(key int) bool
+	Len() int
+	Cap() int
+	Cost() int64
+	Clear()
+	Close() error
+	OnEvict(hook func(key int, value T, reason cache.EvictReason))
+	GetOrLoad(ctx context.Context, key int, loader func(ctx context.Context, key int) (T, error)) (T, error)
+	Stats() cache.Stats
+	ResetStats()
+	All() iter.Seq2[int, T]
+	Backward() iter.Seq2[int, T]
+	Keys() iter.Seq[int]
+	Values() iter.Seq[T]
+}
+
+// Constructors of every policy, used to run the same tests against all of them.
+var testCacheConstructors = map[string]func(cap int, opts ...cache.Option) testCache[string]{
+	"LRU": func(cap int, opts ...cache.Option) testCache[string] {
+		return cache.MustNewLRU[int, string](cap, opts...)
+	},
+	"MRU": func(cap int, opts ...cache.Option) testCache[string] {
+		return cache.MustNewMRU[int, string](cap, opts...)
+	},
+	"LFU": func(cap int, opts ...cache.Option) testCache[string] {
+		return cache.MustNewLFU[int, string](cap, opts...)
+	},
+	"ARC": func(cap int, opts ...cache.Option) testCache[string] {
+		return cache.MustNewARC[int, string](cap, opts...)
+	},
+	"TwoQueue": func(cap int, opts ...cache.Option) testCache[string] {
+		return cache.MustNewTwoQueue[int, string](cap, opts...)
+	},
+	"SLRU": func(cap int, opts ...cache.Option) testCache[string] {
+		return cache.MustNewSLRU[int, string](cap, 0.8, opts...)
+	},
+	"TinyLFU": func(cap int, opts ...cache.Option) testCache[string] {
+		return cache.MustNewTinyLFU[int, string](cap, opts...)
+	},
+	// A single shard behaves like the cache it wraps
+	"Sharded": func(cap int, opts ...cache.Option) testCache[string] {
+		return cache.MustNewSharded(1, nil, func() cache.Cache[int, string] {
+			return cache.MustNewLRU[int, string](cap, opts...)
+		})
+	},
+}
+
+func TestNewWithInvalidOptions(t *testing.T) {
+	if _, err := cache.NewLRU[int, int](1, cache.WithTTL(-time.Second)); err == nil {
+		t.Errorf(testFailedMsg, "TestNewWithInvalidOptions", "error", err)
+	}
+	if _, err := cache.NewMRU[int, int](1, cache.WithJanitor(-time.Second)); err == nil {
+		t.Errorf(testFailedMsg, "TestNewWithInvalidOptions", "error", err)
+	}
+	if _, err := cache.NewLRU[int, int](1, cache.WithClock(nil)); err == nil {
+		t.Errorf(testFailedMsg, "TestNewWithInvalidOptions", "error", err)
+	}
+	if _, err := cache.NewARC[int, int](1, cache.WithNegativeCaching(-time.Second)); err == nil {
+		t.Errorf(testFailedMsg, "TestNewWithInvalidOptions", "error", err)
+	}
+	if _, err := cache.NewSLRU[int, int](1, 0.8, cache.WithCodec(nil)); err == nil {
+		t.Errorf(testFailedMsg, "TestNewWithInvalidOptions", "error", err)
+	}
+}
+
+func TestPutWithTTL(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		clock := newFakeClock()
+		c := newCache(3, cache.WithClock(clock.Now))
+
+		c.PutWithTTL(1, "A", time.Second)
+		c.PutWithTTL(2, "B", time.Minute)
+		c.Put(3, "C")
+
+		// Nothing is expired yet
+		clock.Advance(time.Second - time.Nanosecond)
+		if val, err := c.Get(1); err != nil {
+			t.Errorf(testFailedMsg, "TestPutWithTTL "+name, "nil error", err)
+		} else if val != "A" {
+			t.Errorf(testFailedMsg, "TestPutWithTTL "+name, "A", val)
+		}
+
+		// A is expired, it should be treated as missing
+		clock.Advance(time.Nanosecond)
+		if _, err := c.Get(1); !errors.Is(err, collection.ErrNotFound) {
+			t.Errorf(testFailedMsg, "TestPutWithTTL "+name, collection.ErrNotFound, err)
+		}
+		if c.Len() != 2 {
+			t.Errorf(testFailedMsg, "TestPutWithTTL "+name, 2, c.Len())
+		}
+
+		// B is expired, C never expires
+		clock.Advance(time.Hour)
+		if c.Contains(2) {
+			t.Errorf(testFailedMsg, "TestPutWithTTL "+name, false, true)
+		}
+		if _, err := c.Peek(2); !errors.Is(err, collection.ErrNotFound) {
+			t.Errorf(testFailedMsg, "TestPutWithTTL "+name, collection.ErrNotFound, err)
+		}
+		if val, err := c.Peek(3); err != nil {
+			t.Errorf(testFailedMsg, "TestPutWithTTL "+name, "nil error", err)
+		} else if val != "C" {
+			t.Errorf(testFailedMsg, "TestPutWithTTL "+name, "C", val)
+		}
+
+		// Putting again resets the TTL
+		c.PutWithTTL(3, "CC", time.Second)
+		clock.Advance(time.Second)
+		if c.Delete(3) {
+			t.Errorf(testFailedMsg, "TestPutWithTTL "+name, false, true)
+		}
+		if c.Len() != 0 {
+			t.Errorf(testFailedMsg, "TestPutWithTTL "+name, 0, c.Len())
+		}
+	}
+}
+
+func TestDefaultTTL(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		clock := newFakeClock()
+		c := newCache(2, cache.WithTTL(time.Minute), cache.WithClock(clock.Now))
+		c.Put(1, "A")
+		// A zero ttl overrides the default, the entry never expires
+		c.PutWithTTL(2, "B", 0)
+
+		clock.Advance(time.Minute)
+		if _, err := c.Get(1); !errors.Is(err, collection.ErrNotFound) {
+			t.Errorf(testFailedMsg, "TestDefaultTTL "+name, collection.ErrNotFound, err)
+		}
+		if val, err := c.Get(2); err != nil {
+			t.Errorf(testFailedMsg, "TestDefaultTTL "+name, "nil error", err)
+		} else if val != "B" {
+			t.Errorf(testFailedMsg, "TestDefaultTTL "+name, "B", val)
+		}
+	}
+}
+
+func TestJanitor(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		clock := newFakeClock()
+		c := newCache(10, cache.WithClock(clock.Now), cache.WithJanitor(time.Millisecond))
+		for i := 0; i < 5; i++ {
+			c.PutWithTTL(i, "A", time.Second)
+		}
+		c.Put(5, "B")
+
+		// Expired entries should be removed in the background without being accessed
+		clock.Advance(time.Second)
+		deadline := time.Now().Add(5 * time.Second)
+		for c.Len() != 1 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if c.Len() != 1 {
+			t.Errorf(testFailedMsg, "TestJanitor "+name, 1, c.Len())
+		}
+
+		// Close should be safe to call more than once
+		if err := c.Close(); err != nil {
+			t.Errorf(testFailedMsg, "TestJanitor "+name, "nil error", err)
+		}
+		if err := c.Close(); err != nil {
+			t.Errorf(testFailedMsg, "TestJanitor "+name, "nil error", err)
+		}
+
+		// The janitor is stopped, expired entries are no longer removed in the background
+		c.PutWithTTL(6, "C", time.Second)
+		clock.Advance(time.Second)
+		time.Sleep(10 * time.Millisecond)
+		if c.Len() != 2 {
+			t.Errorf(testFailedMsg, "TestJanitor "+name, 2, c.Len())
+		}
+	}
+}
+
+func TestCacheRace(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		var wg sync.WaitGroup
+		c := newCache(randint(10, 50))
+		functions := []func(){
+			// Put to the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					c.Put(randint(0, 100), name)
+				}
+			},
+
+			// Get from the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_, _ = c.Get(randint(0, 100))
+				}
+			},
+
+			// Peek at the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_, _ = c.Peek(randint(0, 100))
+				}
+			},
+
+			// Delete from the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_ = c.Delete(randint(0, 100))
+				}
+			},
+
+			// Check if the cache contains a key
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_ = c.Contains(randint(0, 100))
+					_ = c.Len()
+				}
+			},
+		}
+
+		wg.Add(len(functions))
+		for _, f := range functions {
+			go f()
+		}
+		wg.Wait()
+	}
+}
+
+func TestTTLRace(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		var wg sync.WaitGroup
+		c := newCache(randint(10, 50), cache.WithTTL(time.Millisecond), cache.WithJanitor(time.Millisecond))
+		functions := []func(){
+			// Put to the cache with the default TTL
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					c.Put(randint(0, 100), name)
+				}
+			},
+
+			// Put to the cache with a TTL
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					c.PutWithTTL(randint(0, 100), name, time.Duration(randint(1, 1000))*time.Microsecond)
+				}
+			},
+
+			// Get from the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_, _ = c.Get(randint(0, 100))
+				}
+			},
+		}
+
+		wg.Add(len(functions))
+		for _, f := range functions {
+			go f()
+		}
+		wg.Wait()
+		_ = c.Close()
+	}
+}
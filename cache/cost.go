@@ -0,0 +1,27 @@
+package cache
+
+import "fmt"
+
+// OversizedError is returned when putting an entry whose cost alone is greater than the capacity of the cache.
+// Such an entry is never kept by the cache.
+type OversizedError struct {
+	// The cost of the rejected entry.
+	Cost int64
+	// The maximum total cost of the cache.
+	MaxCost int64
+}
+
+func (e *OversizedError) Error() string {
+	return fmt.Sprintf("entry cost of %d exceeds the max cost of %d", e.Cost, e.MaxCost)
+}
+
+// Check the arguments of the constructors of caches with a cost function.
+func checkCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64) error {
+	if maxCost < 1 {
+		return fmt.Errorf("invalid specified max cost of %d", maxCost)
+	}
+	if cost == nil {
+		return fmt.Errorf("cost function is required")
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package cache_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/trviph/collection/cache"
+)
+
+// The cost of an entry is the length of its value.
+func testCost(key int, value string) int64 {
+	return int64(len(value))
+}
+
+// Constructors of every policy with a cost function, used to run the same tests against all of them.
+var testCostCacheConstructors = map[string]func(maxCost int64, opts ...cache.Option) testCache[string]{
+	"LRU": func(maxCost int64, opts ...cache.Option) testCache[string] {
+		return cache.MustNewLRUWithCost(maxCost, testCost, opts...)
+	},
+	"MRU": func(maxCost int64, opts ...cache.Option) testCache[string] {
+		return cache.MustNewMRUWithCost(maxCost, testCost, opts...)
+	},
+	"LFU": func(maxCost int64, opts ...cache.Option) testCache[string] {
+		return cache.MustNewLFUWithCost(maxCost, testCost, opts...)
+	},
+	"ARC": func(maxCost int64, opts ...cache.Option) testCache[string] {
+		return cache.MustNewARCWithCost(maxCost, testCost, opts...)
+	},
+	"TwoQueue": func(maxCost int64, opts ...cache.Option) testCache[string] {
+		return cache.MustNewTwoQueueWithCost(maxCost, testCost, opts...)
+	},
+	"SLRU": func(maxCost int64, opts ...cache.Option) testCache[string] {
+		return cache.MustNewSLRUWithCost(maxCost, 0.8, testCost, opts...)
+	},
+	"TinyLFU": func(maxCost int64, opts ...cache.Option) testCache[string] {
+		return cache.MustNewTinyLFUWithCost(maxCost, testCost, opts...)
+	},
+}
+
+// Sum the cost of entries of the given keys that are in the cache.
+func sumCost(c testCache[string], keys ...int) int64 {
+	var sum int64
+	for _, key := range keys {
+		if val, err := c.Peek(key); err == nil {
+			sum += testCost(key, val)
+		}
+	}
+	return sum
+}
+
+func TestNewWithCost(t *testing.T) {
+	if _, err := cache.NewLRUWithCost(0, testCost); err == nil {
+		t.Errorf(testFailedMsg, "TestNewWithCost", "error", err)
+	}
+	if _, err := cache.NewARCWithCost[int, string](10, nil); err == nil {
+		t.Errorf(testFailedMsg, "TestNewWithCost", "error", err)
+	}
+	if _, err := cache.NewSLRUWithCost(10, 1.5, testCost); err == nil {
+		t.Errorf(testFailedMsg, "TestNewWithCost", "error", err)
+	}
+	if _, err := cache.NewTinyLFUWithCost(10, testCost, cache.WithTTL(-time.Second)); err == nil {
+		t.Errorf(testFailedMsg, "TestNewWithCost", "error", err)
+	}
+	if _, err := cache.NewTwoQueueWithCost(10, testCost); err != nil {
+		t.Errorf(testFailedMsg, "TestNewWithCost", "nil error", err)
+	}
+}
+
+func TestMustNewWithCost(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewWithCost", "panic", r)
+		}
+	}()
+	_ = cache.MustNewLFUWithCost(-1, testCost)
+}
+
+func TestCost(t *testing.T) {
+	for name, newCache := range testCostCacheConstructors {
+		clock := newFakeClock()
+		c := newCache(10, cache.WithClock(clock.Now))
+		if c.Cap() != 10 {
+			t.Errorf(testFailedMsg, "TestCost "+name, 10, c.Cap())
+		}
+
+		c.Put(1, "AAAA")
+		c.Put(2, "BBBB")
+		if c.Cost() != 8 {
+			t.Errorf(testFailedMsg, "TestCost "+name, 8, c.Cost())
+		}
+
+		// One of the entries is evicted so the new one fits
+		c.Put(3, "CCCCC")
+		if !c.Contains(3) {
+			t.Errorf(testFailedMsg, "TestCost "+name, true, false)
+		}
+		if c.Len() != 2 || c.Cost() != 9 || c.Cost() != sumCost(c, 1, 2, 3) {
+			t.Errorf(testFailedMsg, "TestCost "+name, 9, c.Cost())
+		}
+
+		// Updating with the same cost keeps the other entries
+		c.Put(3, "DDDDD")
+		if c.Len() != 2 || c.Cost() != 9 {
+			t.Errorf(testFailedMsg, "TestCost "+name, 9, c.Cost())
+		}
+
+		// Updating with a greater cost evicts until it fits
+		c.Put(3, "DDDDDDDDDD")
+		if val, err := c.Peek(3); err != nil || val != "DDDDDDDDDD" {
+			t.Errorf(testFailedMsg, "TestCost "+name, "DDDDDDDDDD", val)
+		}
+		if c.Len() != 1 || c.Cost() != 10 {
+			t.Errorf(testFailedMsg, "TestCost "+name, 10, c.Cost())
+		}
+
+		// Removing entries releases their cost
+		c.PutWithTTL(4, "E", time.Second)
+		clock.Advance(time.Second)
+		_, _ = c.Get(4)
+		if c.Cost() != sumCost(c, 3) {
+			t.Errorf(testFailedMsg, "TestCost "+name, sumCost(c, 3), c.Cost())
+		}
+		c.Delete(3)
+		if c.Cost() != 0 {
+			t.Errorf(testFailedMsg, "TestCost "+name, 0, c.Cost())
+		}
+		c.Put(5, "F")
+		c.Clear()
+		if c.Cost() != 0 {
+			t.Errorf(testFailedMsg, "TestCost "+name, 0, c.Cost())
+		}
+	}
+}
+
+func TestCostWithoutCostFunction(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(3)
+		for key := range 5 {
+			c.Put(key, "AAAA")
+		}
+		if c.Cost() != 3 {
+			t.Errorf(testFailedMsg, "TestCostWithoutCostFunction "+name, 3, c.Cost())
+		}
+	}
+}
+
+func TestOversized(t *testing.T) {
+	for name, newCache := range testCostCacheConstructors {
+		c := newCache(10)
+		c.Put(1, "A")
+		c.Put(2, "BB")
+
+		var oversized *cache.OversizedError
+		if err := c.TryPut(3, strings.Repeat("C", 11)); !errors.As(err, &oversized) {
+			t.Errorf(testFailedMsg, "TestOversized "+name, "oversized error", err)
+		} else if oversized.Cost != 11 || oversized.MaxCost != 10 {
+			t.Errorf(testFailedMsg, "TestOversized "+name, "cost of 11 and max cost of 10", oversized)
+		}
+		if c.Contains(3) || c.Cost() != 3 {
+			t.Errorf(testFailedMsg, "TestOversized "+name, 3, c.Cost())
+		}
+
+		// The old value of an existing key is removed
+		c.Put(2, strings.Repeat("B", 11))
+		if c.Contains(2) || c.Cost() != 1 {
+			t.Errorf(testFailedMsg, "TestOversized "+name, 1, c.Cost())
+		}
+
+		if err := c.TryPutWithTTL(4, strings.Repeat("D", 10), time.Minute); err != nil {
+			t.Errorf(testFailedMsg, "TestOversized "+name, "nil error", err)
+		}
+		if c.Len() != 1 || c.Cost() != 10 {
+			t.Errorf(testFailedMsg, "TestOversized "+name, 10, c.Cost())
+		}
+	}
+}
+
+func TestCostInvariant(t *testing.T) {
+	keys := make([]int, 50)
+	for i := range keys {
+		keys[i] = i
+	}
+
+	for name, newCache := range testCostCacheConstructors {
+		c := newCache(100)
+		for i := 0; i < 5000; i++ {
+			key := randint(0, 50)
+			switch randint(0, 4) {
+			case 0, 1:
+				c.Put(key, strings.Repeat("A", randint(0, 30)))
+			case 2:
+				_, _ = c.Get(key)
+			case 3:
+				_ = c.Delete(key)
+			}
+
+			if c.Cost() > 100 || c.Cost() != sumCost(c, keys...) {
+				t.Fatalf(testFailedMsg, "TestCostInvariant "+name, sumCost(c, keys...), c.Cost())
+			}
+		}
+	}
+}
+
+func TestCostRace(t *testing.T) {
+	for _, newCache := range testCostCacheConstructors {
+		var wg sync.WaitGroup
+		c := newCache(int64(randint(50, 200)))
+		functions := []func(){
+			// Put to the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_ = c.TryPut(randint(0, 100), strings.Repeat("A", randint(0, 50)))
+				}
+			},
+
+			// Get from the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_, _ = c.Get(randint(0, 100))
+				}
+			},
+
+			// Delete from the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_ = c.Delete(randint(0, 100))
+				}
+			},
+
+			// Check the cost of the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_ = c.Cost()
+				}
+			},
+		}
+
+		wg.Add(len(functions))
+		for _, f := range functions {
+			go f()
+		}
+		wg.Wait()
+	}
+}
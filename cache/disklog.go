@@ -0,0 +1,485 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// The name of the log file in the directory of a disk log.
+const diskLogName = "tiered.log"
+
+// Every log file starts with these bytes, followed by the version of the format as a big-endian uint16.
+var diskLogMagic = []byte("TVTIERED")
+
+// The version of the format of log files, it should be increased on every incompatible change of the format.
+const diskLogVersion uint16 = 1
+
+// The size of the header of a log file.
+var diskLogHeaderSize = int64(len(diskLogMagic) + 2)
+
+// Every record is framed by its size and its CRC-32 as big-endian uint32, followed by the record encoded by the codec.
+const diskFrameHeaderSize = 8
+
+// Compaction runs on its own once the garbage in the log is larger than this, and larger than the live records.
+const diskLogCompactGarbage = 1 << 20
+
+// A record of the log, encoded by the codec.
+type diskRecord[K comparable, T any] struct {
+	Key   K
+	Value T
+	// The moment the entry expires, the zero time means the entry never expires.
+	ExpiresAt time.Time
+	// A deleted record removes the entry written by the previous records of the key.
+	Deleted bool
+}
+
+// Where the latest record of a key is in the log file.
+type diskRef struct {
+	// The offset of the frame of the record.
+	offset int64
+	// The size of the frame of the record, including its header.
+	size      int64
+	expiresAt time.Time
+}
+
+func (r diskRef) expired(now time.Time) bool {
+	return !r.expiresAt.IsZero() && !now.Before(r.expiresAt)
+}
+
+// An append-only log of entries in a file, with an in-memory index of where the latest record of every key is.
+// Records are never changed once written, so putting or deleting a key leaves its previous record as garbage,
+// which is removed by compaction, rewriting the live records to a new file.
+type diskLog[K comparable, T any] struct {
+	mu    sync.Mutex
+	path  string
+	file  *os.File
+	codec Codec
+	now   func() time.Time
+
+	index map[K]diskRef
+	// The size of the log file, where the next record is written.
+	size int64
+	// The total size of the records in the index, the rest of the file after the header is garbage.
+	live int64
+}
+
+// Open the log file in the given directory, creating both if they do not exist.
+// The records of an existing log file are read back into the index.
+func openDiskLog[K comparable, T any](dir string, codec Codec, now func() time.Time) (*diskLog[K, T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, diskLogName)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &diskLog[K, T]{
+		path:  path,
+		file:  file,
+		codec: codec,
+		now:   now,
+		index: make(map[K]diskRef),
+	}
+	if err := l.replay(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Read the log file from the start to build the index, or write the header of an empty log file.
+// A record torn by a crash while being written is dropped along with everything after it.
+func (l *diskLog[K, T]) replay() error {
+	info, err := l.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		l.size = diskLogHeaderSize
+		return writeDiskLogHeader(l.file)
+	}
+
+	header := make([]byte, diskLogHeaderSize)
+	if _, err := l.file.ReadAt(header, 0); err != nil || !bytes.Equal(header[:len(diskLogMagic)], diskLogMagic) {
+		return fmt.Errorf("%s is not a log of a tiered cache", l.path)
+	}
+	if version := binary.BigEndian.Uint16(header[len(diskLogMagic):]); version != diskLogVersion {
+		return fmt.Errorf("%s is a log of unsupported version %d", l.path, version)
+	}
+
+	now := l.now()
+	r := bufio.NewReader(io.NewSectionReader(l.file, diskLogHeaderSize, info.Size()-diskLogHeaderSize))
+	offset := diskLogHeaderSize
+	for {
+		payload, err := readDiskFrame(r, info.Size()-offset)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// The end of the file is torn, drop it
+			if err := l.file.Truncate(offset); err != nil {
+				return err
+			}
+			break
+		}
+		rec, err := l.decode(payload)
+		if err != nil {
+			return fmt.Errorf("failed to read %s; cause by %w", l.path, err)
+		}
+
+		ref := diskRef{offset: offset, size: int64(diskFrameHeaderSize + len(payload)), expiresAt: rec.ExpiresAt}
+		offset += ref.size
+		if old, ok := l.index[rec.Key]; ok {
+			delete(l.index, rec.Key)
+			l.live -= old.size
+		}
+		if !rec.Deleted && !ref.expired(now) {
+			l.index[rec.Key] = ref
+			l.live += ref.size
+		}
+	}
+	l.size = offset
+	return nil
+}
+
+// Read the next frame of at most the given size and return its payload, or io.EOF if there is no more frame.
+func readDiskFrame(r io.Reader, maxSize int64) ([]byte, error) {
+	header := make([]byte, diskFrameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if int64(size) > maxSize-diskFrameHeaderSize {
+		return nil, io.ErrUnexpectedEOF
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+	return payload, nil
+}
+
+func writeDiskLogHeader(w io.Writer) error {
+	header := make([]byte, diskLogHeaderSize)
+	copy(header, diskLogMagic)
+	binary.BigEndian.PutUint16(header[len(diskLogMagic):], diskLogVersion)
+	_, err := w.Write(header)
+	return err
+}
+
+func (l *diskLog[K, T]) decode(payload []byte) (diskRecord[K, T], error) {
+	var rec diskRecord[K, T]
+	err := l.codec.NewDecoder(bytes.NewReader(payload)).Decode(&rec)
+	return rec, err
+}
+
+// Append a record to the end of the log file, and return where it is.
+func (l *diskLog[K, T]) write(rec diskRecord[K, T]) (diskRef, error) {
+	if l.file == nil {
+		return diskRef{}, fmt.Errorf("log is closed")
+	}
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, diskFrameHeaderSize))
+	if err := l.codec.NewEncoder(&buf).Encode(rec); err != nil {
+		return diskRef{}, err
+	}
+	frame := buf.Bytes()
+	payload := frame[diskFrameHeaderSize:]
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:], crc32.ChecksumIEEE(payload))
+
+	if _, err := l.file.WriteAt(frame, l.size); err != nil {
+		// Do not leave a partial record behind
+		_ = l.file.Truncate(l.size)
+		return diskRef{}, err
+	}
+	ref := diskRef{offset: l.size, size: int64(len(frame)), expiresAt: rec.ExpiresAt}
+	l.size += ref.size
+	return ref, nil
+}
+
+// Read the record a reference points to.
+func (l *diskLog[K, T]) read(ref diskRef) (diskRecord[K, T], error) {
+	payload := make([]byte, ref.size-diskFrameHeaderSize)
+	if _, err := l.file.ReadAt(payload, ref.offset+diskFrameHeaderSize); err != nil {
+		return diskRecord[K, T]{}, err
+	}
+	return l.decode(payload)
+}
+
+// Forget the record of a key, so it becomes garbage.
+func (l *diskLog[K, T]) forget(key K) {
+	if ref, ok := l.index[key]; ok {
+		delete(l.index, key)
+		l.live -= ref.size
+	}
+}
+
+// Find the live record of a key, forgetting it if it is expired.
+func (l *diskLog[K, T]) lookup(key K) (diskRef, bool) {
+	ref, ok := l.index[key]
+	if !ok {
+		return diskRef{}, false
+	}
+	if ref.expired(l.now()) {
+		l.forget(key)
+		return diskRef{}, false
+	}
+	return ref, true
+}
+
+// Put writes an entry to the log, replacing the previous entry of the key if there is any.
+func (l *diskLog[K, T]) put(key K, value T, expiresAt time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !expiresAt.IsZero() && !l.now().Before(expiresAt) {
+		return nil
+	}
+	ref, err := l.write(diskRecord[K, T]{Key: key, Value: value, ExpiresAt: expiresAt})
+	if err != nil {
+		return err
+	}
+	l.forget(key)
+	l.index[key] = ref
+	l.live += ref.size
+	return l.maybeCompact()
+}
+
+// Get reads the entry of a key from the log.
+// It returns false if there is no such key or the entry is expired.
+func (l *diskLog[K, T]) get(key K) (T, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var zeroValue T
+	ref, ok := l.lookup(key)
+	if !ok {
+		return zeroValue, false, nil
+	}
+	rec, err := l.read(ref)
+	if err != nil {
+		return zeroValue, false, err
+	}
+	return rec.Value, true, nil
+}
+
+// Take reads the entry of a key from the log, then deletes it,
+// and returns the moment it expires along with its value.
+func (l *diskLog[K, T]) take(key K) (T, time.Time, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var zeroValue T
+	ref, ok := l.lookup(key)
+	if !ok {
+		return zeroValue, time.Time{}, false, nil
+	}
+	rec, err := l.read(ref)
+	if err != nil {
+		return zeroValue, time.Time{}, false, err
+	}
+	if err := l.remove(key); err != nil {
+		return zeroValue, time.Time{}, false, err
+	}
+	return rec.Value, rec.ExpiresAt, true, nil
+}
+
+// Delete removes the entry of a key from the log, it returns true if the key was in the log and not expired.
+func (l *diskLog[K, T]) delete(key K) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.lookup(key); !ok {
+		return false, nil
+	}
+	return true, l.remove(key)
+}
+
+// Write a deleted record for a key in the index, so it is not read back when the log is opened again.
+func (l *diskLog[K, T]) remove(key K) error {
+	if _, err := l.write(diskRecord[K, T]{Key: key, Deleted: true}); err != nil {
+		return err
+	}
+	l.forget(key)
+	return l.maybeCompact()
+}
+
+// Contains reports whether the key is in the log and not expired.
+func (l *diskLog[K, T]) contains(key K) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, ok := l.lookup(key)
+	return ok
+}
+
+// Len returns the number of entries in the log that are not expired.
+func (l *diskLog[K, T]) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	count := 0
+	for _, ref := range l.index {
+		if !ref.expired(now) {
+			count++
+		}
+	}
+	return count
+}
+
+// Keys returns the keys of the entries in the log that are not expired, from the latest written to the earliest.
+func (l *diskLog[K, T]) keys() []K {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	refs := l.liveRefs()
+	keys := make([]K, len(refs))
+	for i, kr := range refs {
+		keys[len(refs)-1-i] = kr.key
+	}
+	return keys
+}
+
+// A key along with the reference to its record.
+type diskKeyRef[K comparable] struct {
+	key K
+	ref diskRef
+}
+
+// Get the references of the records that are not expired, in the order they were written.
+func (l *diskLog[K, T]) liveRefs() []diskKeyRef[K] {
+	now := l.now()
+	refs := make([]diskKeyRef[K], 0, len(l.index))
+	for key, ref := range l.index {
+		if !ref.expired(now) {
+			refs = append(refs, diskKeyRef[K]{key: key, ref: ref})
+		}
+	}
+	slices.SortFunc(refs, func(a, b diskKeyRef[K]) int {
+		return cmp.Compare(a.ref.offset, b.ref.offset)
+	})
+	return refs
+}
+
+// Clear removes every entry from the log by truncating the log file.
+func (l *diskLog[K, T]) clear() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	clear(l.index)
+	l.live = 0
+	if l.file == nil {
+		return nil
+	}
+	if err := l.file.Truncate(diskLogHeaderSize); err != nil {
+		return err
+	}
+	l.size = diskLogHeaderSize
+	return nil
+}
+
+// Compact rewrites the live records to a new log file, which then replaces the current one.
+func (l *diskLog[K, T]) compact() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.rewrite()
+}
+
+// Compact the log if there is enough garbage in it.
+func (l *diskLog[K, T]) maybeCompact() error {
+	garbage := l.size - diskLogHeaderSize - l.live
+	if garbage <= diskLogCompactGarbage || garbage <= l.live {
+		return nil
+	}
+	return l.rewrite()
+}
+
+// Rewrite the live records to a new log file while holding the mutex.
+// The records keep the order they were written in.
+func (l *diskLog[K, T]) rewrite() error {
+	if l.file == nil {
+		return fmt.Errorf("log is closed")
+	}
+
+	tmpPath := l.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	fail := func(err error) error {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	refs := l.liveRefs()
+	w := bufio.NewWriter(tmp)
+	if err := writeDiskLogHeader(w); err != nil {
+		return fail(err)
+	}
+	index := make(map[K]diskRef, len(refs))
+	offset := diskLogHeaderSize
+	for _, kr := range refs {
+		frame := make([]byte, kr.ref.size)
+		if _, err := l.file.ReadAt(frame, kr.ref.offset); err != nil {
+			return fail(err)
+		}
+		if _, err := w.Write(frame); err != nil {
+			return fail(err)
+		}
+		index[kr.key] = diskRef{offset: offset, size: kr.ref.size, expiresAt: kr.ref.expiresAt}
+		offset += kr.ref.size
+	}
+	if err := w.Flush(); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := os.Rename(tmpPath, l.path); err != nil {
+		return fail(err)
+	}
+
+	_ = l.file.Close()
+	l.file = tmp
+	l.index = index
+	l.size = offset
+	l.live = offset - diskLogHeaderSize
+	return nil
+}
+
+// Close closes the log file, the log is empty afterward.
+// The entries in the file are read back when it is opened again.
+func (l *diskLog[K, T]) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	clear(l.index)
+	l.live = 0
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -1,7 +1,20 @@
 package cache
 
+import "time"
+
 // A cache entry consist of a key of hashable type and a value of any type
 type entry[K comparable, T any] struct {
 	key   K
 	value T
+
+	// The moment the entry expires, the zero time means the entry never expires.
+	expiresAt time.Time
+	// How much of the cache capacity the entry takes, it is 1 unless the cache is created with a cost function.
+	cost int64
+	// When the entry was last put or used, entries with a greater value are used more recently.
+	used uint64
+}
+
+func (e *entry[K, T]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
 }
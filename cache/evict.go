@@ -0,0 +1,40 @@
+package cache
+
+import "time"
+
+// EvictReason tells why an entry was removed from a cache.
+type EvictReason int
+
+const (
+	// The entry was dropped by the eviction policy to make room for other entries.
+	EvictCapacity EvictReason = iota
+	// The entry was removed by Delete or Clear.
+	EvictDeleted
+	// The entry was removed because its TTL has passed.
+	EvictExpired
+	// The entry value was overwritten by a Put with the same key.
+	EvictReplaced
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictDeleted:
+		return "deleted"
+	case EvictExpired:
+		return "expired"
+	case EvictReplaced:
+		return "replaced"
+	default:
+		return "unknown"
+	}
+}
+
+// An entry that was removed from the cache, waiting to be passed to the eviction hooks.
+type eviction[K comparable, T any] struct {
+	key       K
+	value     T
+	expiresAt time.Time
+	reason    EvictReason
+}
@@ -0,0 +1,162 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trviph/collection/cache"
+)
+
+type testEviction struct {
+	key    int
+	value  string
+	reason cache.EvictReason
+}
+
+func TestOnEvict(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		clock := newFakeClock()
+		c := newCache(2, cache.WithClock(clock.Now))
+
+		var got []testEviction
+		c.OnEvict(func(key int, value string, reason cache.EvictReason) {
+			got = append(got, testEviction{key: key, value: value, reason: reason})
+		})
+
+		c.Put(1, "A")
+		c.Put(1, "AA")
+		c.Put(2, "B")
+		// Evict one of the two entries by capacity
+		c.Put(3, "C")
+		c.PutWithTTL(4, "D", time.Second)
+		clock.Advance(time.Second)
+		_, _ = c.Get(4)
+		c.Put(5, "E")
+		c.Delete(5)
+		c.Put(6, "F")
+		c.Clear()
+
+		wants := map[string][]testEviction{
+			"LRU": {
+				{key: 1, value: "A", reason: cache.EvictReplaced},
+				{key: 1, value: "AA", reason: cache.EvictCapacity},
+				{key: 2, value: "B", reason: cache.EvictCapacity},
+				{key: 4, value: "D", reason: cache.EvictExpired},
+				{key: 5, value: "E", reason: cache.EvictDeleted},
+				{key: 6, value: "F", reason: cache.EvictDeleted},
+				{key: 3, value: "C", reason: cache.EvictDeleted},
+			},
+			"MRU": {
+				{key: 1, value: "A", reason: cache.EvictReplaced},
+				{key: 2, value: "B", reason: cache.EvictCapacity},
+				{key: 3, value: "C", reason: cache.EvictCapacity},
+				{key: 4, value: "D", reason: cache.EvictExpired},
+				{key: 5, value: "E", reason: cache.EvictDeleted},
+				{key: 6, value: "F", reason: cache.EvictDeleted},
+				{key: 1, value: "AA", reason: cache.EvictDeleted},
+			},
+			"LFU": {
+				{key: 1, value: "A", reason: cache.EvictReplaced},
+				{key: 2, value: "B", reason: cache.EvictCapacity},
+				{key: 3, value: "C", reason: cache.EvictCapacity},
+				{key: 4, value: "D", reason: cache.EvictExpired},
+				{key: 5, value: "E", reason: cache.EvictDeleted},
+				{key: 1, value: "AA", reason: cache.EvictDeleted},
+				{key: 6, value: "F", reason: cache.EvictDeleted},
+			},
+			"ARC": {
+				{key: 1, value: "A", reason: cache.EvictReplaced},
+				{key: 2, value: "B", reason: cache.EvictCapacity},
+				{key: 3, value: "C", reason: cache.EvictCapacity},
+				{key: 4, value: "D", reason: cache.EvictExpired},
+				{key: 5, value: "E", reason: cache.EvictDeleted},
+				{key: 1, value: "AA", reason: cache.EvictDeleted},
+				{key: 6, value: "F", reason: cache.EvictDeleted},
+			},
+			"TwoQueue": {
+				{key: 1, value: "A", reason: cache.EvictReplaced},
+				{key: 1, value: "AA", reason: cache.EvictCapacity},
+				{key: 2, value: "B", reason: cache.EvictCapacity},
+				{key: 4, value: "D", reason: cache.EvictExpired},
+				{key: 5, value: "E", reason: cache.EvictDeleted},
+				{key: 6, value: "F", reason: cache.EvictDeleted},
+				{key: 3, value: "C", reason: cache.EvictDeleted},
+			},
+			"SLRU": {
+				{key: 1, value: "A", reason: cache.EvictReplaced},
+				{key: 2, value: "B", reason: cache.EvictCapacity},
+				{key: 3, value: "C", reason: cache.EvictCapacity},
+				{key: 4, value: "D", reason: cache.EvictExpired},
+				{key: 5, value: "E", reason: cache.EvictDeleted},
+				{key: 1, value: "AA", reason: cache.EvictDeleted},
+				{key: 6, value: "F", reason: cache.EvictDeleted},
+			},
+			"TinyLFU": {
+				{key: 1, value: "A", reason: cache.EvictReplaced},
+				{key: 2, value: "B", reason: cache.EvictCapacity},
+				{key: 3, value: "C", reason: cache.EvictCapacity},
+				{key: 4, value: "D", reason: cache.EvictExpired},
+				{key: 5, value: "E", reason: cache.EvictDeleted},
+				{key: 6, value: "F", reason: cache.EvictDeleted},
+				{key: 1, value: "AA", reason: cache.EvictDeleted},
+			},
+		}
+		want := wants[name]
+		if name == "Sharded" {
+			want = wants["LRU"]
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf(testFailedMsg, "TestOnEvict "+name, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf(testFailedMsg, "TestOnEvict "+name, want[i], got[i])
+			}
+		}
+	}
+}
+
+func TestOnEvictCallBack(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(1)
+
+		// The hook calls back into the cache, this should not deadlock
+		c.OnEvict(func(key int, value string, reason cache.EvictReason) {
+			if key < 10 {
+				c.Put(key+10, value)
+			}
+		})
+		c.Put(1, "A")
+		c.Put(2, "B")
+
+		// Putting 2 evicted 1, which put 11 and evicted 2, which put 12 and evicted 11
+		if val, err := c.Get(12); err != nil {
+			t.Errorf(testFailedMsg, "TestOnEvictCallBack "+name, "nil error", err)
+		} else if val != "B" {
+			t.Errorf(testFailedMsg, "TestOnEvictCallBack "+name, "B", val)
+		}
+
+		// Removing the hook
+		c.OnEvict(nil)
+		c.Put(3, "C")
+		if c.Contains(13) {
+			t.Errorf(testFailedMsg, "TestOnEvictCallBack "+name, false, true)
+		}
+	}
+}
+
+func TestEvictReasonString(t *testing.T) {
+	reasons := map[cache.EvictReason]string{
+		cache.EvictCapacity:   "capacity",
+		cache.EvictDeleted:    "deleted",
+		cache.EvictExpired:    "expired",
+		cache.EvictReplaced:   "replaced",
+		cache.EvictReason(-1): "unknown",
+	}
+	for reason, want := range reasons {
+		if reason.String() != want {
+			t.Errorf(testFailedMsg, "TestEvictReasonString", want, reason.String())
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package cache
+
+// Expose the ghost lists to the tests of cache_test.
+
+// TwoQueueGhosts returns the number and the total cost of the keys remembered in a1out.
+func TwoQueueGhosts[K comparable, T any](c *TwoQueue[K, T]) (int, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.a1out.len(), c.a1out.cost()
+}
+
+// ARCGhosts returns the number and the total cost of the keys remembered in b1 and b2.
+func ARCGhosts[K comparable, T any](c *ARC[K, T]) (int, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.b1.len() + c.b2.len(), c.b1.cost() + c.b2.cost()
+}
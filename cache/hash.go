@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// Hash keys of any comparable type into uint64, with a random seed chosen on creation.
+// Keys of basic types, and of named types defined on them such as type ID string, are hashed from their value,
+// other keys are hashed from their Go-syntax representation which is much slower.
+// Keys that are equal get the same hash, so -0 and +0 floats are hashed the same,
+// except when they are found in keys hashed from their Go-syntax representation.
+type keyHasher[K comparable] struct {
+	seed maphash.Seed
+}
+
+func newKeyHasher[K comparable]() keyHasher[K] {
+	return keyHasher[K]{seed: maphash.MakeSeed()}
+}
+
+func (h keyHasher[K]) hash(key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(h.seed, k)
+	case int:
+		return h.hashUint64(uint64(k))
+	case int8:
+		return h.hashUint64(uint64(k))
+	case int16:
+		return h.hashUint64(uint64(k))
+	case int32:
+		return h.hashUint64(uint64(k))
+	case int64:
+		return h.hashUint64(uint64(k))
+	case uint:
+		return h.hashUint64(uint64(k))
+	case uint8:
+		return h.hashUint64(uint64(k))
+	case uint16:
+		return h.hashUint64(uint64(k))
+	case uint32:
+		return h.hashUint64(uint64(k))
+	case uint64:
+		return h.hashUint64(k)
+	case uintptr:
+		return h.hashUint64(uint64(k))
+	case float32:
+		return h.hashFloat(float64(k))
+	case float64:
+		return h.hashFloat(k)
+	}
+
+	// Named types are not matched above, but can still be hashed from the value of their underlying type
+	value := reflect.ValueOf(key)
+	switch value.Kind() {
+	case reflect.String:
+		return maphash.String(h.seed, value.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return h.hashUint64(uint64(value.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return h.hashUint64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return h.hashFloat(value.Float())
+	default:
+		return maphash.String(h.seed, fmt.Sprintf("%#v", key))
+	}
+}
+
+func (h keyHasher[K]) hashUint64(value uint64) uint64 {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], value)
+	return maphash.Bytes(h.seed, buf[:])
+}
+
+// Hash a float from its bits, with -0 turned into +0 since they are equal keys.
+func (h keyHasher[K]) hashFloat(value float64) uint64 {
+	if value == 0 {
+		value = 0
+	}
+	return h.hashUint64(math.Float64bits(value))
+}
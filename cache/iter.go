@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"iter"
+	"slices"
+)
+
+// All returns an iterator of the keys and values in the cache,
+// going from the most recently used entry to the least recently used one.
+// An entry is used when it is put, or got by Get or GetOrLoad.
+// Iterating does not mark entries as used, and skips expired entries.
+//
+// The iterator works on a snapshot of the cache, taken when the iteration starts while holding the cache mutex.
+// The mutex is released before the first value is yielded, so the loop body may call any method of the cache,
+// and changes made to the cache during the iteration are not seen by it.
+//
+//	for key, val := range lru.All() {
+//	   // code goes here
+//	}
+func (c *core[K, T]) All() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		for _, e := range c.snapshot() {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward is like All, but goes from the least recently used entry to the most recently used one.
+func (c *core[K, T]) Backward() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		entries := c.snapshot()
+		for i := len(entries) - 1; i >= 0; i-- {
+			if !yield(entries[i].key, entries[i].value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator of the keys in the cache, in the same order and with the same semantics as All.
+func (c *core[K, T]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range c.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator of the values in the cache, in the same order and with the same semantics as All.
+func (c *core[K, T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, val := range c.All() {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+// Copy the live entries of the cache, from the most recently used to the least recently used.
+func (c *core[K, T]) snapshot() []entry[K, T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	entries := make([]entry[K, T], 0, c.policy.len())
+	for e := range c.policy.entries() {
+		if !e.expired(now) {
+			entries = append(entries, *e)
+		}
+	}
+	slices.SortFunc(entries, func(a, b entry[K, T]) int {
+		// The more recently used, the earlier
+		switch {
+		case a.used > b.used:
+			return -1
+		case a.used < b.used:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return entries
+}
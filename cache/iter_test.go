@@ -0,0 +1,184 @@
+package cache_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/trviph/collection/cache"
+)
+
+func TestAll(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		clock := newFakeClock()
+		c := newCache(4, cache.WithClock(clock.Now))
+		c.Put(1, "A")
+		c.Put(2, "B")
+		c.Put(3, "C")
+		c.PutWithTTL(4, "D", time.Second)
+		_, _ = c.Get(1)
+		// Peek does not mark the key as used, and expired entries are skipped
+		_, _ = c.Peek(2)
+		clock.Advance(time.Second)
+
+		wantKeys := []int{1, 3, 2}
+		wantValues := []string{"A", "C", "B"}
+		gotKeys := make([]int, 0)
+		gotValues := make([]string, 0)
+		for key, val := range c.All() {
+			gotKeys = append(gotKeys, key)
+			gotValues = append(gotValues, val)
+		}
+		if !slices.Equal(wantKeys, gotKeys) || !slices.Equal(wantValues, gotValues) {
+			t.Errorf(testFailedMsg, "TestAll "+name, wantKeys, gotKeys)
+		}
+		if got := slices.Collect(c.Keys()); !slices.Equal(wantKeys, got) {
+			t.Errorf(testFailedMsg, "TestAll "+name, wantKeys, got)
+		}
+		if got := slices.Collect(c.Values()); !slices.Equal(wantValues, got) {
+			t.Errorf(testFailedMsg, "TestAll "+name, wantValues, got)
+		}
+
+		gotKeys = gotKeys[:0]
+		for key := range c.Backward() {
+			gotKeys = append(gotKeys, key)
+		}
+		if want := []int{2, 3, 1}; !slices.Equal(want, gotKeys) {
+			t.Errorf(testFailedMsg, "TestAll "+name, want, gotKeys)
+		}
+
+		// Iterating does not count as hits
+		if c.Stats().Hits != 1 {
+			t.Errorf(testFailedMsg, "TestAll "+name, 1, c.Stats().Hits)
+		}
+	}
+}
+
+func TestAllBreak(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(3)
+		for key := range 3 {
+			c.Put(key, "A")
+		}
+
+		count := 0
+		for range c.Keys() {
+			count++
+			break
+		}
+		for range c.Values() {
+			count++
+			break
+		}
+		for range c.Backward() {
+			count++
+			break
+		}
+		if count != 3 {
+			t.Errorf(testFailedMsg, "TestAllBreak "+name, 3, count)
+		}
+	}
+}
+
+func TestAllSnapshot(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(10)
+		c.Put(1, "A")
+		c.Put(2, "B")
+
+		// The cache can be used inside the loop, changes are not seen by the iteration
+		got := make([]int, 0)
+		for key := range c.All() {
+			got = append(got, key)
+			c.Put(key+10, "C")
+			c.Delete(1)
+		}
+		if want := []int{2, 1}; !slices.Equal(want, got) {
+			t.Errorf(testFailedMsg, "TestAllSnapshot "+name, want, got)
+		}
+		if c.Len() != 3 {
+			t.Errorf(testFailedMsg, "TestAllSnapshot "+name, 3, c.Len())
+		}
+	}
+}
+
+func TestAllNoPromote(t *testing.T) {
+	lru := cache.MustNewLRU[int, string](2)
+	lru.Put(1, "A")
+	lru.Put(2, "B")
+	for range lru.All() {
+	}
+
+	// 1 is still the least recently used
+	lru.Put(3, "C")
+	if lru.Contains(1) {
+		t.Errorf(testFailedMsg, "TestAllNoPromote", false, true)
+	}
+}
+
+func TestShardedAll(t *testing.T) {
+	sharded := cache.MustNewSharded(2, testShardHash, func() cache.Cache[int, string] {
+		return cache.MustNewLRU[int, string](3)
+	})
+	for key := range 4 {
+		sharded.Put(key, "A")
+	}
+	_, _ = sharded.Get(0)
+
+	// Shard by shard, each from the most recently used
+	if want, got := []int{0, 2, 3, 1}, slices.Collect(sharded.Keys()); !slices.Equal(want, got) {
+		t.Errorf(testFailedMsg, "TestShardedAll", want, got)
+	}
+	gotKeys := make([]int, 0)
+	for key := range sharded.Backward() {
+		gotKeys = append(gotKeys, key)
+	}
+	if want := []int{1, 3, 2, 0}; !slices.Equal(want, gotKeys) {
+		t.Errorf(testFailedMsg, "TestShardedAll", want, gotKeys)
+	}
+	if got := len(slices.Collect(sharded.Values())); got != 4 {
+		t.Errorf(testFailedMsg, "TestShardedAll", 4, got)
+	}
+}
+
+func TestAllRace(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		var wg sync.WaitGroup
+		c := newCache(randint(10, 50))
+		functions := []func(){
+			// Put to the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					c.Put(randint(0, 100), name)
+				}
+			},
+
+			// Iterate over the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 100); i++ {
+					for key := range c.All() {
+						_ = c.Contains(key)
+					}
+				}
+			},
+
+			// Iterate backward over the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 100); i++ {
+					for range c.Backward() {
+					}
+				}
+			},
+		}
+
+		wg.Add(len(functions))
+		for _, f := range functions {
+			go f()
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// A janitor periodically runs a clean up function in its own goroutine until it is stopped.
+type janitor struct {
+	once sync.Once
+	stop chan struct{}
+	done chan struct{}
+}
+
+func startJanitor(interval time.Duration, clean func()) *janitor {
+	j := &janitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(j.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				clean()
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+	return j
+}
+
+// Stop the janitor and wait for its goroutine to exit, it is safe to call more than once.
+func (j *janitor) close() {
+	j.once.Do(func() {
+		close(j.stop)
+		<-j.done
+	})
+}
@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/internal"
+)
+
+// A cache using the Least Frequently Used eviction policy.
+// Ties between entries used the same number of times are broken by recency,
+// the least recently used of them is evicted first.
+// Put, Get and eviction are all O(1), by grouping entries into buckets of the same frequency.
+//
+// [Least Frequently Used (LFU)]: https://en.wikipedia.org/wiki/Least_frequently_used
+// [An O(1) algorithm for implementing the LFU cache eviction scheme]: http://dhruvbird.com/lfu.pdf
+type LFU[K comparable, T any] struct {
+	core[K, T]
+
+	// Keeping track of which *internal.Node is holding an entry by its key.
+	entryNodes map[K]*internal.Node[*lfuEntry[K, T]]
+
+	// Keeping track of the frequency of entries.
+	// Buckets are ordered from least frequently used to most frequently used, going from head to tail.
+	entryFrequency *collection.List[*lfuBucket[K, T]]
+}
+
+// A bucket holding all entries that have been used the same number of times.
+type lfuBucket[K comparable, T any] struct {
+	frequency int
+
+	// Entries are ordered from most recently used to least recently used, going from head to tail.
+	entries *collection.List[*lfuEntry[K, T]]
+}
+
+// An entry with the node of the bucket it belongs to.
+type lfuEntry[K comparable, T any] struct {
+	*entry[K, T]
+	bucket *internal.Node[*lfuBucket[K, T]]
+}
+
+var _ internal.Cache[int, any] = (*LFU[int, any])(nil)
+
+// [NewLFU] creates a new cache with [LFU] eviction policy.
+// It accepts cap as the first argument, specifying the maximum capacity of the cache,
+// followed by any [Option] to configure the cache.
+// It will return an error if cap is less than 1 or an option is invalid.
+func NewLFU[K comparable, T any](cap int, opts ...Option) (*LFU[K, T], error) {
+	if cap < 1 {
+		return nil, fmt.Errorf("failed to create lfu; cause by invalid specified capacity")
+	}
+	return newLFU[K, T](int64(cap), nil, opts)
+}
+
+// Like [NewLFU] but will panic on error.
+func MustNewLFU[K comparable, T any](cap int, opts ...Option) *LFU[K, T] {
+	return collection.Must(
+		func() (*LFU[K, T], error) {
+			return NewLFU[K, T](cap, opts...)
+		},
+	)
+}
+
+// [NewLFUWithCost] is like [NewLFU], but the capacity of the cache is measured by the cost of entries,
+// instead of the number of entries. It accepts maxCost as the first argument, specifying the maximum total cost of the cache,
+// and cost computing the cost of an entry, which should not be negative.
+// It will return an error if maxCost is less than 1, cost is nil or an option is invalid.
+func NewLFUWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) (*LFU[K, T], error) {
+	if err := checkCost(maxCost, cost); err != nil {
+		return nil, fmt.Errorf("failed to create lfu; cause by %w", err)
+	}
+	return newLFU(maxCost, cost, opts)
+}
+
+// Like [NewLFUWithCost] but will panic on error.
+func MustNewLFUWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) *LFU[K, T] {
+	return collection.Must(
+		func() (*LFU[K, T], error) {
+			return NewLFUWithCost(maxCost, cost, opts...)
+		},
+	)
+}
+
+func newLFU[K comparable, T any](cap int64, cost func(key K, value T) int64, opts []Option) (*LFU[K, T], error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lfu; cause by %w", err)
+	}
+
+	c := &LFU[K, T]{
+		entryNodes:     make(map[K]*internal.Node[*lfuEntry[K, T]]),
+		entryFrequency: collection.NewList[*lfuBucket[K, T]](),
+	}
+	c.init(cap, cost, c, o)
+	return c, nil
+}
+
+func (c *LFU[K, T]) lookup(key K) (*entry[K, T], bool) {
+	node, ok := c.entryNodes[key]
+	if !ok {
+		return nil, false
+	}
+	return node.Value.entry, true
+}
+
+func (c *LFU[K, T]) touch(e *entry[K, T]) {
+	node := c.entryNodes[e.key]
+	bucket := node.Value.bucket
+
+	// Move the entry to the bucket of the next frequency, create the bucket if it does not exist
+	next := bucket.Right
+	frequency := bucket.Value.frequency + 1
+	if next == nil || next.Value.frequency != frequency {
+		next = c.entryFrequency.InsertAfter(bucket, newLFUBucket[K, T](frequency))
+	}
+	c.unlink(node)
+	c.link(node.Value.entry, next)
+}
+
+func (c *LFU[K, T]) miss(key K) {}
+
+func (c *LFU[K, T]) add(e *entry[K, T]) {
+	// New entries are used once, so they belong to the first bucket
+	head := c.entryFrequency.Head()
+	if head == nil || head.Value.frequency != 1 {
+		c.entryFrequency.Prepend(newLFUBucket[K, T](1))
+		head = c.entryFrequency.Head()
+	}
+	c.link(e, head)
+}
+
+func (c *LFU[K, T]) remove(e *entry[K, T]) {
+	c.unlink(c.entryNodes[e.key])
+	delete(c.entryNodes, e.key)
+}
+
+func (c *LFU[K, T]) evict() *entry[K, T] {
+	// The least recently used entry of the least frequently used bucket
+	head := c.entryFrequency.Head()
+	if head == nil {
+		// This should never happend
+		panic(
+			fmt.Errorf("something went very wrong; cannot drop LFU entry of cache with capacity of %d, entries length %d", c.cap, len(c.entryNodes)),
+		)
+	}
+	node := head.Value.entries.Tail()
+	c.unlink(node)
+	delete(c.entryNodes, node.Value.key)
+	return node.Value.entry
+}
+
+func (c *LFU[K, T]) len() int {
+	return len(c.entryNodes)
+}
+
+func (c *LFU[K, T]) clear() {
+	c.entryNodes = make(map[K]*internal.Node[*lfuEntry[K, T]])
+	c.entryFrequency = collection.NewList[*lfuBucket[K, T]]()
+}
+
+// Iterate from the most frequently used to the least frequently used entry.
+func (c *LFU[K, T]) entries() iter.Seq[*entry[K, T]] {
+	return func(yield func(*entry[K, T]) bool) {
+		for _, bucket := range c.entryFrequency.Backward() {
+			for _, e := range bucket.entries.All() {
+				if !yield(e.entry) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Add an entry as the most recently used entry of a bucket.
+func (c *LFU[K, T]) link(e *entry[K, T], bucket *internal.Node[*lfuBucket[K, T]]) {
+	bucket.Value.entries.Prepend(&lfuEntry[K, T]{entry: e, bucket: bucket})
+	c.entryNodes[e.key] = bucket.Value.entries.Head()
+}
+
+// Remove an entry from its bucket, and remove the bucket if it becomes empty.
+func (c *LFU[K, T]) unlink(node *internal.Node[*lfuEntry[K, T]]) {
+	bucket := node.Value.bucket
+	bucket.Value.entries.RemoveNode(node)
+	if bucket.Value.entries.Length() == 0 {
+		c.entryFrequency.RemoveNode(bucket)
+	}
+}
+
+func newLFUBucket[K comparable, T any](frequency int) *lfuBucket[K, T] {
+	return &lfuBucket[K, T]{
+		frequency: frequency,
+		entries:   collection.NewList[*lfuEntry[K, T]](),
+	}
+}
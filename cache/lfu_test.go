@@ -0,0 +1,142 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/cache"
+)
+
+func TestNewLFU(t *testing.T) {
+	_, err := cache.NewLFU[int, int](0)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewLFU", "error", err)
+	}
+	_, err = cache.NewLFU[int, int](1)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestNewLFU", "nil error", err)
+	}
+}
+
+func TestMustNewLFU(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewLFU", "panic", r)
+		}
+	}()
+	_ = cache.MustNewLFU[int, any](-1)
+}
+
+func TestLFU(t *testing.T) {
+	// Create a cache the only hold 3 values at maximum
+	lfu, err := cache.NewLFU[int, string](3)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestLFU", "nil error", err)
+	}
+
+	// Should get is empty error
+	if _, err := lfu.Get(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestLFU", collection.ErrIsEmpty, err)
+	}
+
+	// A is used 3 times, B is used 2 times and C is used once
+	lfu.Put(1, "A")
+	lfu.Put(2, "B")
+	lfu.Put(3, "C")
+	_, _ = lfu.Get(1)
+	_, _ = lfu.Get(1)
+	lfu.Put(2, "BB")
+
+	// C is the least frequently used, so it should be evicted
+	lfu.Put(4, "D")
+	if _, err := lfu.Get(3); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestLFU", collection.ErrNotFound, err)
+	}
+	if val, err := lfu.Get(2); err != nil {
+		t.Errorf(testFailedMsg, "TestLFU", "nil error", err)
+	} else if val != "BB" {
+		t.Errorf(testFailedMsg, "TestLFU", "BB", val)
+	}
+
+	// D is used once, so it should be evicted even though it is the most recently used
+	lfu.Put(5, "E")
+	if _, err := lfu.Get(4); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestLFU", collection.ErrNotFound, err)
+	}
+
+	// Peek does not count as a use, so E is still the least frequently used
+	_, _ = lfu.Peek(5)
+	lfu.Put(6, "F")
+	if lfu.Contains(5) {
+		t.Errorf(testFailedMsg, "TestLFU", false, true)
+	}
+	for _, key := range []int{1, 2, 6} {
+		if !lfu.Contains(key) {
+			t.Errorf(testFailedMsg, "TestLFU", true, false)
+		}
+	}
+}
+
+func TestLFUTieBreak(t *testing.T) {
+	lfu := cache.MustNewLFU[int, string](3)
+	lfu.Put(1, "A")
+	lfu.Put(2, "B")
+	lfu.Put(3, "C")
+
+	// All entries are used twice, A is now the most recently used
+	_, _ = lfu.Get(2)
+	_, _ = lfu.Get(3)
+	_, _ = lfu.Get(1)
+
+	// B is the least recently used among the least frequently used
+	lfu.Put(4, "D")
+	if lfu.Contains(2) {
+		t.Errorf(testFailedMsg, "TestLFUTieBreak", false, true)
+	}
+
+	// D is used twice and becomes the most recently used, so C is evicted next
+	_, _ = lfu.Get(4)
+	lfu.Put(5, "E")
+	if lfu.Contains(3) {
+		t.Errorf(testFailedMsg, "TestLFUTieBreak", false, true)
+	}
+	for _, key := range []int{1, 4, 5} {
+		if !lfu.Contains(key) {
+			t.Errorf(testFailedMsg, "TestLFUTieBreak", true, false)
+		}
+	}
+}
+
+func TestLFUDelete(t *testing.T) {
+	lfu := cache.MustNewLFU[int, string](3)
+	lfu.Put(1, "A")
+	lfu.Put(2, "B")
+	lfu.Put(3, "C")
+	_, _ = lfu.Get(1)
+	_, _ = lfu.Get(2)
+	_, _ = lfu.Get(2)
+
+	// Delete the only entry of each frequency bucket
+	for _, key := range []int{1, 3, 2} {
+		if !lfu.Delete(key) {
+			t.Errorf(testFailedMsg, "TestLFUDelete", true, false)
+		}
+	}
+	if lfu.Len() != 0 {
+		t.Errorf(testFailedMsg, "TestLFUDelete", 0, lfu.Len())
+	}
+
+	// The cache should still work after all entries are deleted
+	lfu.Put(4, "D")
+	lfu.Put(5, "E")
+	lfu.Put(6, "F")
+	_, _ = lfu.Get(4)
+	lfu.Put(7, "G")
+	if lfu.Contains(5) {
+		t.Errorf(testFailedMsg, "TestLFUDelete", false, true)
+	}
+	if lfu.Len() != 3 {
+		t.Errorf(testFailedMsg, "TestLFUDelete", 3, lfu.Len())
+	}
+}
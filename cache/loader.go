@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// A load of a missing key in progress, shared by every GetOrLoad call on the key.
+type load[T any] struct {
+	// Closed once the load is done, after value and err are set.
+	done  chan struct{}
+	value T
+	err   error
+	// Set when the key is deleted or the cache is cleared during the load, so the loaded value is not put.
+	discarded bool
+}
+
+// PanicError is returned by GetOrLoad when the loader panics, to every call waiting for the load.
+type PanicError struct {
+	// The value the loader panicked with.
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("loader panicked with %v", e.Value)
+}
+
+// The error of a failed load, kept until it expires when negative caching is enabled.
+type failure struct {
+	err       error
+	expiresAt time.Time
+}
+
+// GetOrLoad gets the value associated with the given key like Get,
+// or if there is no such key, calls loader to load the value and puts it into the cache.
+// Concurrent calls for the same missing key share a single call of loader, and all get its result.
+//
+// An error returned by loader is returned as is, and is not cached unless [WithNegativeCaching] is given.
+// If ctx is done before the value is loaded, GetOrLoad returns the error of ctx without waiting.
+// The loader keeps running in its own goroutine with a context that is never canceled,
+// so the loaded value is still put into the cache for later calls.
+// If the key is put while it is being loaded, the loaded value does not replace the put one,
+// and if the key is deleted or the cache is cleared, the loaded value is returned but not put.
+// If loader panics, the panic is recovered and returned as a [*PanicError].
+func (c *core[K, T]) GetOrLoad(ctx context.Context, key K, loader func(ctx context.Context, key K) (T, error)) (T, error) {
+	c.mu.Lock()
+	if e, err := c.find(key); err == nil {
+		c.stats.recordHit()
+		c.markUsed(e)
+		c.unlock()
+		return e.value, nil
+	}
+	c.stats.recordMiss()
+	if f, ok := c.failures[key]; ok {
+		if c.now().Before(f.expiresAt) {
+			c.unlock()
+			var zeroValue T
+			return zeroValue, f.err
+		}
+		delete(c.failures, key)
+	}
+
+	// Join the load of the key in progress, or start a new one
+	l, ok := c.loads[key]
+	if !ok {
+		l = &load[T]{done: make(chan struct{})}
+		c.loads[key] = l
+		go c.load(context.WithoutCancel(ctx), key, l, loader)
+	}
+	c.unlock()
+
+	select {
+	case <-l.done:
+		return l.value, l.err
+	case <-ctx.Done():
+		var zeroValue T
+		return zeroValue, ctx.Err()
+	}
+}
+
+// Call the loader, then put the loaded value or keep the error, and wake up the calls waiting for it.
+func (c *core[K, T]) load(ctx context.Context, key K, l *load[T], loader func(ctx context.Context, key K) (T, error)) {
+	defer close(l.done)
+	start := time.Now()
+	l.value, l.err = callLoader(ctx, key, loader)
+	c.stats.recordLoad(time.Since(start), l.err)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if l.discarded {
+		return
+	}
+	delete(c.loads, key)
+	if l.err != nil {
+		if c.negativeTTL > 0 {
+			c.failures[key] = &failure{err: l.err, expiresAt: c.now().Add(c.negativeTTL)}
+		}
+		return
+	}
+	if _, ok := c.policy.lookup(key); !ok {
+		_ = c.put(key, l.value, c.ttl)
+	}
+}
+
+// Stop the load of the key in progress from putting its value, later calls start a new load.
+func (c *core[K, T]) discardLoad(key K) {
+	if l, ok := c.loads[key]; ok {
+		l.discarded = true
+		delete(c.loads, key)
+	}
+}
+
+// Call the loader, recovering its panic as a [*PanicError].
+func callLoader[K comparable, T any](ctx context.Context, key K, loader func(ctx context.Context, key K) (T, error)) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r}
+		}
+	}()
+	return loader(ctx, key)
+}
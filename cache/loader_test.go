@@ -0,0 +1,313 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/trviph/collection/cache"
+)
+
+func TestGetOrLoad(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(2)
+		var calls atomic.Int32
+		loader := func(ctx context.Context, key int) (string, error) {
+			calls.Add(1)
+			return fmt.Sprint(key), nil
+		}
+
+		// The first call loads the value and puts it, the second one gets it from the cache
+		for range 2 {
+			if val, err := c.GetOrLoad(context.Background(), 1, loader); err != nil {
+				t.Errorf(testFailedMsg, "TestGetOrLoad "+name, "nil error", err)
+			} else if val != "1" {
+				t.Errorf(testFailedMsg, "TestGetOrLoad "+name, "1", val)
+			}
+		}
+		if calls.Load() != 1 {
+			t.Errorf(testFailedMsg, "TestGetOrLoad "+name, 1, calls.Load())
+		}
+		if val, err := c.Peek(1); err != nil || val != "1" {
+			t.Errorf(testFailedMsg, "TestGetOrLoad "+name, "1", val)
+		}
+
+		// A value already in the cache is not loaded
+		c.Put(2, "B")
+		if val, _ := c.GetOrLoad(context.Background(), 2, loader); val != "B" {
+			t.Errorf(testFailedMsg, "TestGetOrLoad "+name, "B", val)
+		}
+		if calls.Load() != 1 {
+			t.Errorf(testFailedMsg, "TestGetOrLoad "+name, 1, calls.Load())
+		}
+	}
+}
+
+func TestGetOrLoadSingleflight(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(10)
+		var calls atomic.Int32
+		release := make(chan struct{})
+		loader := func(ctx context.Context, key int) (string, error) {
+			calls.Add(1)
+			<-release
+			return "A", nil
+		}
+
+		var wg sync.WaitGroup
+		values := make([]string, 10)
+		wg.Add(len(values))
+		for i := range values {
+			go func() {
+				defer wg.Done()
+				values[i], _ = c.GetOrLoad(context.Background(), 1, loader)
+			}()
+		}
+
+		// Let the calls pile up on the load in progress
+		deadline := time.Now().Add(5 * time.Second)
+		for calls.Load() == 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		if calls.Load() != 1 {
+			t.Errorf(testFailedMsg, "TestGetOrLoadSingleflight "+name, 1, calls.Load())
+		}
+		for _, val := range values {
+			if val != "A" {
+				t.Errorf(testFailedMsg, "TestGetOrLoadSingleflight "+name, "A", val)
+			}
+		}
+	}
+}
+
+func TestGetOrLoadError(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(2)
+		errLoad := errors.New("load failed")
+		var calls atomic.Int32
+		loader := func(ctx context.Context, key int) (string, error) {
+			calls.Add(1)
+			return "", errLoad
+		}
+
+		// Errors are not cached by default
+		for range 2 {
+			if _, err := c.GetOrLoad(context.Background(), 1, loader); !errors.Is(err, errLoad) {
+				t.Errorf(testFailedMsg, "TestGetOrLoadError "+name, errLoad, err)
+			}
+		}
+		if calls.Load() != 2 {
+			t.Errorf(testFailedMsg, "TestGetOrLoadError "+name, 2, calls.Load())
+		}
+		if c.Len() != 0 {
+			t.Errorf(testFailedMsg, "TestGetOrLoadError "+name, 0, c.Len())
+		}
+	}
+}
+
+func TestGetOrLoadNegativeCaching(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		clock := newFakeClock()
+		c := newCache(2, cache.WithClock(clock.Now), cache.WithNegativeCaching(time.Minute))
+		errLoad := errors.New("load failed")
+		var calls atomic.Int32
+		loader := func(ctx context.Context, key int) (string, error) {
+			calls.Add(1)
+			return "", errLoad
+		}
+
+		// The error is kept until the negative caching ttl has passed
+		for range 2 {
+			if _, err := c.GetOrLoad(context.Background(), 1, loader); !errors.Is(err, errLoad) {
+				t.Errorf(testFailedMsg, "TestGetOrLoadNegativeCaching "+name, errLoad, err)
+			}
+		}
+		if calls.Load() != 1 {
+			t.Errorf(testFailedMsg, "TestGetOrLoadNegativeCaching "+name, 1, calls.Load())
+		}
+		clock.Advance(time.Minute)
+		_, _ = c.GetOrLoad(context.Background(), 1, loader)
+		if calls.Load() != 2 {
+			t.Errorf(testFailedMsg, "TestGetOrLoadNegativeCaching "+name, 2, calls.Load())
+		}
+
+		// Putting the key forgets the error
+		c.Put(1, "A")
+		c.Delete(1)
+		_, _ = c.GetOrLoad(context.Background(), 1, loader)
+		if calls.Load() != 3 {
+			t.Errorf(testFailedMsg, "TestGetOrLoadNegativeCaching "+name, 3, calls.Load())
+		}
+	}
+}
+
+func TestGetOrLoadContext(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(2)
+		release := make(chan struct{})
+		loaded := make(chan struct{})
+		loader := func(ctx context.Context, key int) (string, error) {
+			defer close(loaded)
+			<-release
+			// The context of the loader is not canceled with the one of the caller
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return "A", nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		if _, err := c.GetOrLoad(ctx, 1, loader); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf(testFailedMsg, "TestGetOrLoadContext "+name, context.DeadlineExceeded, err)
+		}
+		cancel()
+
+		// The value is still put once loaded
+		close(release)
+		<-loaded
+		if val, err := c.GetOrLoad(context.Background(), 1, loader); err != nil || val != "A" {
+			t.Errorf(testFailedMsg, "TestGetOrLoadContext "+name, "A", val)
+		}
+	}
+}
+
+func TestGetOrLoadDelete(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(2)
+		for _, remove := range []func(){func() { c.Delete(1) }, c.Clear} {
+			started := make(chan struct{})
+			release := make(chan struct{})
+			loader := func(ctx context.Context, key int) (string, error) {
+				close(started)
+				<-release
+				return "stale", nil
+			}
+
+			// The key is removed while it is being loaded, the loaded value is returned but not put
+			res := make(chan string)
+			go func() {
+				val, _ := c.GetOrLoad(context.Background(), 1, loader)
+				res <- val
+			}()
+			<-started
+			remove()
+			close(release)
+			if val := <-res; val != "stale" {
+				t.Errorf(testFailedMsg, "TestGetOrLoadDelete "+name, "stale", val)
+			}
+			if c.Contains(1) {
+				t.Errorf(testFailedMsg, "TestGetOrLoadDelete "+name, false, true)
+			}
+
+			// A later call loads the key again
+			val, err := c.GetOrLoad(context.Background(), 1, func(ctx context.Context, key int) (string, error) {
+				return "fresh", nil
+			})
+			if err != nil || val != "fresh" {
+				t.Errorf(testFailedMsg, "TestGetOrLoadDelete "+name, "fresh", val)
+			}
+			c.Delete(1)
+		}
+	}
+}
+
+func TestGetOrLoadPanic(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(2)
+		release := make(chan struct{})
+		loader := func(ctx context.Context, key int) (string, error) {
+			<-release
+			panic("load panicked")
+		}
+
+		// Every call waiting for the load gets the panic as an error
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		wg.Add(cap(errs))
+		for range cap(errs) {
+			go func() {
+				defer wg.Done()
+				_, err := c.GetOrLoad(context.Background(), 1, loader)
+				errs <- err
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			var panicErr *cache.PanicError
+			if !errors.As(err, &panicErr) {
+				t.Errorf(testFailedMsg, "TestGetOrLoadPanic "+name, "panic error", err)
+			} else if panicErr.Value != "load panicked" {
+				t.Errorf(testFailedMsg, "TestGetOrLoadPanic "+name, "load panicked", panicErr.Value)
+			}
+		}
+		if c.Contains(1) {
+			t.Errorf(testFailedMsg, "TestGetOrLoadPanic "+name, false, true)
+		}
+	}
+}
+
+func TestGetOrLoadRace(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		var wg sync.WaitGroup
+		c := newCache(randint(10, 50))
+		loader := func(ctx context.Context, key int) (string, error) {
+			if key%10 == 0 {
+				return "", errors.New("load failed")
+			}
+			return name, nil
+		}
+		functions := []func(){
+			// Get or load from the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_, _ = c.GetOrLoad(context.Background(), randint(0, 100), loader)
+				}
+			},
+
+			// Get or load from the cache, giving up early
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancel()
+					_, _ = c.GetOrLoad(ctx, randint(0, 100), loader)
+				}
+			},
+
+			// Put to the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					c.Put(randint(0, 100), name)
+				}
+			},
+
+			// Delete from the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_ = c.Delete(randint(0, 100))
+				}
+			},
+		}
+
+		wg.Add(len(functions))
+		for _, f := range functions {
+			go f()
+		}
+		wg.Wait()
+	}
+}
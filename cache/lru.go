@@ -2,7 +2,7 @@ package cache
 
 import (
 	"fmt"
-	"sync"
+	"iter"
 
 	"github.com/trviph/collection"
 	"github.com/trviph/collection/internal"
@@ -12,113 +12,187 @@ import (
 //
 // [Least Recently Used (LRU)]: https://en.wikipedia.org/wiki/Cache_replacement_policies#Least_Recently_Used_(LRU)
 type LRU[K comparable, T any] struct {
-	mu  sync.Mutex
-	cap int
-
-	// Keeping track of which *internal.Node is holding an entry by its key.
-	entryNodes map[K]*internal.Node[*entry[K, T]]
-
-	// Keeping track of the recency of entries.
-	// Entries are ordered from most recently used to least recently used, going from head to tail.
-	entryRecency *collection.List[*entry[K, T]]
+	core[K, T]
+	lruList[K, T]
 }
 
 var _ internal.Cache[int, any] = (*LRU[int, any])(nil)
 
 // [NewLRU] creates a new cache with [LRU] eviction policy.
-// It accepts cap as the only argument, specifying the maximum capacity of the cache.
-// It will return an error if cap is less than 1.
-func NewLRU[K comparable, T any](cap int) (*LRU[K, T], error) {
+// It accepts cap as the first argument, specifying the maximum capacity of the cache,
+// followed by any [Option] to configure the cache.
+// It will return an error if cap is less than 1 or an option is invalid.
+func NewLRU[K comparable, T any](cap int, opts ...Option) (*LRU[K, T], error) {
 	if cap < 1 {
 		return nil, fmt.Errorf("failed to create lru; cause by invalid specified capacity")
 	}
-	return &LRU[K, T]{
-		cap:          cap,
-		entryNodes:   make(map[K]*internal.Node[*entry[K, T]]),
-		entryRecency: collection.NewList[*entry[K, T]](),
-	}, nil
+	return newLRU[K, T](int64(cap), nil, opts)
 }
 
 // Like [NewLRU] but will panic on error.
-func MustNewLRU[K comparable, T any](cap int) *LRU[K, T] {
+func MustNewLRU[K comparable, T any](cap int, opts ...Option) *LRU[K, T] {
 	return collection.Must(
 		func() (*LRU[K, T], error) {
-			return NewLRU[K, T](cap)
+			return NewLRU[K, T](cap, opts...)
 		},
 	)
 }
 
-// Put a new value with an associated key into the cache.
-// This will update the value if the key already exist.
-func (c *LRU[K, T]) Put(key K, value T) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// If key already existed
-	if _, ok := c.entryNodes[key]; ok {
-		c.updateEntry(key, value)
-	} else {
-		c.evict()
-		c.newEntry(key, value)
+// [NewLRUWithCost] is like [NewLRU], but the capacity of the cache is measured by the cost of entries,
+// instead of the number of entries. It accepts maxCost as the first argument, specifying the maximum total cost of the cache,
+// and cost computing the cost of an entry, which should not be negative.
+// It will return an error if maxCost is less than 1, cost is nil or an option is invalid.
+func NewLRUWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) (*LRU[K, T], error) {
+	if err := checkCost(maxCost, cost); err != nil {
+		return nil, fmt.Errorf("failed to create lru; cause by %w", err)
 	}
+	return newLRU(maxCost, cost, opts)
 }
 
-func (c *LRU[K, T]) evict() {
-	if c.cap > c.entryRecency.Length() {
-		return
-	}
-	entry, err := c.entryRecency.Pop()
+// Like [NewLRUWithCost] but will panic on error.
+func MustNewLRUWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) *LRU[K, T] {
+	return collection.Must(
+		func() (*LRU[K, T], error) {
+			return NewLRUWithCost(maxCost, cost, opts...)
+		},
+	)
+}
+
+func newLRU[K comparable, T any](cap int64, cost func(key K, value T) int64, opts []Option) (*LRU[K, T], error) {
+	o, err := newOptions(opts)
 	if err != nil {
+		return nil, fmt.Errorf("failed to create lru; cause by %w", err)
+	}
+
+	c := &LRU[K, T]{lruList: newLRUList[K, T]()}
+	c.init(cap, cost, c, o)
+	return c, nil
+}
+
+func (c *LRU[K, T]) miss(key K) {}
+
+func (c *LRU[K, T]) touch(e *entry[K, T]) {
+	// Mark it as recently used
+	c.moveToFront(e)
+}
+
+func (c *LRU[K, T]) add(e *entry[K, T]) {
+	// Mark it as recently used
+	c.pushFront(e)
+}
+
+func (c *LRU[K, T]) evict() *entry[K, T] {
+	entry := c.popBack()
+	if entry == nil {
 		// This should never happend
 		panic(
-			fmt.Errorf("something went very wrong; cannot drop LRU entry of cache with capacity of %d, entries length %d", c.cap, c.entryRecency.Length()),
+			fmt.Errorf("something went very wrong; cannot drop LRU entry of cache with capacity of %d, entries length %d", c.cap, c.len()),
 		)
 	}
-	// Delete the entry from lookup map
-	delete(c.entryNodes, entry.key)
+	return entry
 }
 
-func (c *LRU[K, T]) newEntry(key K, value T) {
-	// Mark it as recently used
-	c.entryRecency.Prepend(&entry[K, T]{key: key, value: value})
+// A list of entries ordered by recency, with a map to look up which node is holding an entry.
+// This is the base of [LRU], [MRU], and other policies that are made of several LRU lists.
+type lruList[K comparable, T any] struct {
+	// Keeping track of which *internal.Node is holding an entry by its key.
+	entryNodes map[K]*internal.Node[*entry[K, T]]
+
+	// Keeping track of the recency of entries.
+	// Entries are ordered from most recently used to least recently used, going from head to tail.
+	entryRecency *collection.List[*entry[K, T]]
+
+	// The sum of the cost of all entries in the list.
+	totalCost int64
+}
+
+func newLRUList[K comparable, T any]() lruList[K, T] {
+	return lruList[K, T]{
+		entryNodes:   make(map[K]*internal.Node[*entry[K, T]]),
+		entryRecency: collection.NewList[*entry[K, T]](),
+	}
+}
+
+func (l *lruList[K, T]) lookup(key K) (*entry[K, T], bool) {
+	node, ok := l.entryNodes[key]
+	if !ok {
+		return nil, false
+	}
+	return node.Value, true
+}
+
+// Add a new entry as the most recently used.
+func (l *lruList[K, T]) pushFront(e *entry[K, T]) {
+	l.entryRecency.Prepend(e)
 	// Add new entry to map
-	c.entryNodes[key] = c.entryRecency.Head()
+	l.entryNodes[e.key] = l.entryRecency.Head()
+	l.totalCost += e.cost
 }
 
-func (c *LRU[K, T]) updateEntry(key K, value T) {
-	// Get the node contains the entry
-	oldNode := c.entryNodes[key]
+// Mark an entry of the list as the most recently used.
+func (l *lruList[K, T]) moveToFront(e *entry[K, T]) {
+	l.entryRecency.MoveToFront(l.entryNodes[e.key])
+}
 
-	// Push new value to the recency list
-	c.entryRecency.Prepend(&entry[K, T]{key: key, value: value})
-	// Update the entries map
-	c.entryNodes[key] = c.entryRecency.Head()
+func (l *lruList[K, T]) remove(e *entry[K, T]) {
+	l.entryRecency.RemoveNode(l.entryNodes[e.key])
+	delete(l.entryNodes, e.key)
+	l.totalCost -= e.cost
+}
 
-	// Remove the node from the recency list
-	// If oldNode is tail then Pop, else just unlink
-	if oldNode.Right == nil {
-		_, _ = c.entryRecency.Pop()
-	} else {
-		oldNode.Unlink()
+// Remove and return the most recently used entry, nil if the list is empty.
+func (l *lruList[K, T]) popFront() *entry[K, T] {
+	entry, err := l.entryRecency.Dequeue()
+	if err != nil {
+		return nil
 	}
+	// Delete entry from lookup map
+	delete(l.entryNodes, entry.key)
+	l.totalCost -= entry.cost
+	return entry
 }
 
-// Get the value associated with the given key argument.
-// Get will return [collection.ErrNotFound] if there is no such key,
-// or [collection.ErrIsEmpty] if the cache is empty.
-func (c *LRU[K, T]) Get(key K) (T, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	var zeroValue T
-	if c.entryRecency.Length() == 0 {
-		return zeroValue, collection.ErrIsEmpty
+// Remove and return the least recently used entry, nil if the list is empty.
+func (l *lruList[K, T]) popBack() *entry[K, T] {
+	entry, err := l.entryRecency.Pop()
+	if err != nil {
+		return nil
 	}
-	node, ok := c.entryNodes[key]
-	if !ok {
-		return zeroValue, collection.ErrNotFound
+	// Delete the entry from lookup map
+	delete(l.entryNodes, entry.key)
+	l.totalCost -= entry.cost
+	return entry
+}
+
+// Return the least recently used entry without removing it, nil if the list is empty.
+func (l *lruList[K, T]) back() *entry[K, T] {
+	tail := l.entryRecency.Tail()
+	if tail == nil {
+		return nil
 	}
+	return tail.Value
+}
+
+func (l *lruList[K, T]) len() int {
+	return l.entryRecency.Length()
+}
+
+// The sum of the cost of all entries in the list.
+func (l *lruList[K, T]) cost() int64 {
+	return l.totalCost
+}
+
+func (l *lruList[K, T]) clear() {
+	*l = newLRUList[K, T]()
+}
 
-	entry := node.Value
-	return entry.value, nil
+// Iterate from the most recently used to the least recently used entry.
+func (l *lruList[K, T]) entries() iter.Seq[*entry[K, T]] {
+	return func(yield func(*entry[K, T]) bool) {
+		for _, e := range l.entryRecency.All() {
+			if !yield(e) {
+				return
+			}
+		}
+	}
 }
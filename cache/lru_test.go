@@ -77,6 +77,141 @@ func TestLRU(t *testing.T) {
 	}
 }
 
+func TestLRUGetPromote(t *testing.T) {
+	lru := cache.MustNewLRU[int, string](3)
+	lru.Put(1, "A")
+	lru.Put(2, "B")
+	lru.Put(3, "C")
+
+	// Reading A marks it as recently used, so B is now the least recently used
+	if _, err := lru.Get(1); err != nil {
+		t.Errorf(testFailedMsg, "TestLRUGetPromote", "nil error", err)
+	}
+	lru.Put(4, "D")
+	if _, err := lru.Get(2); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestLRUGetPromote", collection.ErrNotFound, err)
+	}
+
+	// Recency is now D, A, C from most to least recently used,
+	// reading C then A makes D the least recently used
+	_, _ = lru.Get(3)
+	_, _ = lru.Get(1)
+	lru.Put(5, "E")
+	if _, err := lru.Get(4); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestLRUGetPromote", collection.ErrNotFound, err)
+	}
+	for _, key := range []int{1, 3, 5} {
+		if _, err := lru.Get(key); err != nil {
+			t.Errorf(testFailedMsg, "TestLRUGetPromote", "nil error", err)
+		}
+	}
+}
+
+func TestLRUPeek(t *testing.T) {
+	lru := cache.MustNewLRU[int, string](2)
+
+	// Should get is empty error
+	if _, err := lru.Peek(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestLRUPeek", collection.ErrIsEmpty, err)
+	}
+
+	lru.Put(1, "A")
+	lru.Put(2, "B")
+
+	// Peeking A does not mark it as recently used, so A is still evicted
+	if val, err := lru.Peek(1); err != nil {
+		t.Errorf(testFailedMsg, "TestLRUPeek", "nil error", err)
+	} else if val != "A" {
+		t.Errorf(testFailedMsg, "TestLRUPeek", "A", val)
+	}
+	lru.Put(3, "C")
+	if _, err := lru.Peek(1); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestLRUPeek", collection.ErrNotFound, err)
+	}
+}
+
+func TestLRUDelete(t *testing.T) {
+	lru := cache.MustNewLRU[int, string](3)
+	if lru.Delete(1) {
+		t.Errorf(testFailedMsg, "TestLRUDelete", false, true)
+	}
+
+	lru.Put(1, "A")
+	lru.Put(2, "B")
+	lru.Put(3, "C")
+	lru.Put(4, "D")
+	lru.Put(5, "E")
+
+	// Delete the head, the tail and then the last remaining entry
+	for _, key := range []int{5, 3, 4} {
+		if !lru.Delete(key) {
+			t.Errorf(testFailedMsg, "TestLRUDelete", true, false)
+		}
+		if lru.Contains(key) {
+			t.Errorf(testFailedMsg, "TestLRUDelete", false, true)
+		}
+		if _, err := lru.Get(key); err == nil {
+			t.Errorf(testFailedMsg, "TestLRUDelete", "error", err)
+		}
+	}
+	if lru.Len() != 0 {
+		t.Errorf(testFailedMsg, "TestLRUDelete", 0, lru.Len())
+	}
+
+	// The cache should still work after all entries are deleted
+	lru.Put(6, "F")
+	lru.Put(7, "G")
+	lru.Put(8, "H")
+	lru.Put(9, "I")
+	if lru.Len() != 3 {
+		t.Errorf(testFailedMsg, "TestLRUDelete", 3, lru.Len())
+	}
+	if !lru.Contains(9) {
+		t.Errorf(testFailedMsg, "TestLRUDelete", true, false)
+	}
+}
+
+func TestLRUContains(t *testing.T) {
+	lru := cache.MustNewLRU[int, string](2)
+	lru.Put(1, "A")
+	lru.Put(2, "B")
+
+	// Contains does not mark A as recently used
+	if !lru.Contains(1) {
+		t.Errorf(testFailedMsg, "TestLRUContains", true, false)
+	}
+	lru.Put(3, "C")
+	if lru.Contains(1) {
+		t.Errorf(testFailedMsg, "TestLRUContains", false, true)
+	}
+}
+
+func TestLRULenCapClear(t *testing.T) {
+	lru := cache.MustNewLRU[int, string](2)
+	if lru.Cap() != 2 {
+		t.Errorf(testFailedMsg, "TestLRULenCapClear", 2, lru.Cap())
+	}
+
+	lru.Put(1, "A")
+	if lru.Len() != 1 {
+		t.Errorf(testFailedMsg, "TestLRULenCapClear", 1, lru.Len())
+	}
+	lru.Put(1, "AA")
+	lru.Put(2, "B")
+	lru.Put(3, "C")
+	if lru.Len() != 2 {
+		t.Errorf(testFailedMsg, "TestLRULenCapClear", 2, lru.Len())
+	}
+
+	lru.Clear()
+	if lru.Len() != 0 {
+		t.Errorf(testFailedMsg, "TestLRULenCapClear", 0, lru.Len())
+	}
+	if _, err := lru.Get(3); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestLRULenCapClear", collection.ErrIsEmpty, err)
+	}
+}
+
 func TestLRURace(t *testing.T) {
 	var wg sync.WaitGroup
 	lru := cache.MustNewLRU[int, int](randint(10, 50))
@@ -96,6 +231,31 @@ func TestLRURace(t *testing.T) {
 				_, _ = lru.Get(randint(0, 100))
 			}
 		},
+
+		// Peek at the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = lru.Peek(randint(0, 100))
+			}
+		},
+
+		// Delete from the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = lru.Delete(randint(0, 100))
+			}
+		},
+
+		// Check if the cache contains a key
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = lru.Contains(randint(0, 100))
+				_ = lru.Len()
+			}
+		},
 	}
 
 	wg.Add(len(functions))
@@ -2,7 +2,6 @@ package cache
 
 import (
 	"fmt"
-	"sync"
 
 	"github.com/trviph/collection"
 	"github.com/trviph/collection/internal"
@@ -12,116 +11,82 @@ import (
 //
 // [Most Recently Used (MRU)]: https://en.wikipedia.org/wiki/Cache_replacement_policies#Most-recently-used_(MRU)
 type MRU[K comparable, T any] struct {
-	mu  sync.Mutex
-	cap int
-
-	// To keep track and quickly look up which node is holding an entry by its key.
-	entryNodes map[K]*internal.Node[*entry[K, T]]
-
-	// Keeping track of the recency of entries.
-	// Entries are ordered from most recently used to least recently used,
-	// going from head to tail.
-	entryRecency *collection.List[*entry[K, T]]
+	core[K, T]
+	lruList[K, T]
 }
 
 var _ internal.Cache[int, any] = (*MRU[int, any])(nil)
 
-// [NewLRU] creates a new cache with [LRU] eviction policy.
-// It accepts cap as the only argument, specifying the maximum capacity of the cache.
-// Return an error if cap is less than 1.
-func NewMRU[K comparable, T any](cap int) (*MRU[K, T], error) {
+// [NewMRU] creates a new cache with [MRU] eviction policy.
+// It accepts cap as the first argument, specifying the maximum capacity of the cache,
+// followed by any [Option] to configure the cache.
+// Return an error if cap is less than 1 or an option is invalid.
+func NewMRU[K comparable, T any](cap int, opts ...Option) (*MRU[K, T], error) {
 	if cap < 1 {
 		return nil, fmt.Errorf("failed to create mru; cause by invalid specified capacity")
 	}
-	return &MRU[K, T]{
-		cap:          cap,
-		entryNodes:   make(map[K]*internal.Node[*entry[K, T]]),
-		entryRecency: collection.NewList[*entry[K, T]](),
-	}, nil
+	return newMRU[K, T](int64(cap), nil, opts)
 }
 
 // Like [NewMRU] but will panic on error.
-func MustNewMRU[K comparable, T any](cap int) *MRU[K, T] {
+func MustNewMRU[K comparable, T any](cap int, opts ...Option) *MRU[K, T] {
 	return collection.Must(
 		func() (*MRU[K, T], error) {
-			return NewMRU[K, T](cap)
+			return NewMRU[K, T](cap, opts...)
 		},
 	)
 }
 
-// Put a new value with an associated key into the cache.
-// Update the value if the key already exist.
-// This marks the key as recently used.
-func (c *MRU[K, T]) Put(key K, value T) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// If key already existed
-	if _, ok := c.entryNodes[key]; ok {
-		c.updateEntry(key, value)
-	} else {
-		c.evict()
-		c.newEntry(key, value)
+// [NewMRUWithCost] is like [NewMRU], but the capacity of the cache is measured by the cost of entries,
+// instead of the number of entries. It accepts maxCost as the first argument, specifying the maximum total cost of the cache,
+// and cost computing the cost of an entry, which should not be negative.
+// It will return an error if maxCost is less than 1, cost is nil or an option is invalid.
+func NewMRUWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) (*MRU[K, T], error) {
+	if err := checkCost(maxCost, cost); err != nil {
+		return nil, fmt.Errorf("failed to create mru; cause by %w", err)
 	}
+	return newMRU(maxCost, cost, opts)
 }
 
-func (c *MRU[K, T]) evict() {
-	if c.cap > c.entryRecency.Length() {
-		return
-	}
-	entry, err := c.entryRecency.Dequeue()
+// Like [NewMRUWithCost] but will panic on error.
+func MustNewMRUWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) *MRU[K, T] {
+	return collection.Must(
+		func() (*MRU[K, T], error) {
+			return NewMRUWithCost(maxCost, cost, opts...)
+		},
+	)
+}
+
+func newMRU[K comparable, T any](cap int64, cost func(key K, value T) int64, opts []Option) (*MRU[K, T], error) {
+	o, err := newOptions(opts)
 	if err != nil {
-		// This should never happend
-		panic(
-			fmt.Errorf("something went very wrong; cannot drop LRU entry of cache with capacity of %d, entries length %d", c.cap, c.entryRecency.Length()),
-		)
+		return nil, fmt.Errorf("failed to create mru; cause by %w", err)
 	}
-	// Delete entry from lookup map
-	delete(c.entryNodes, entry.key)
-}
 
-func (c *MRU[K, T]) newEntry(key K, value T) {
-	// Mark it as recently used
-	c.entryRecency.Prepend(&entry[K, T]{key: key, value: value})
-	// Add new entry to map
-	c.entryNodes[key] = c.entryRecency.Head()
+	c := &MRU[K, T]{lruList: newLRUList[K, T]()}
+	c.init(cap, cost, c, o)
+	return c, nil
 }
 
-func (c *MRU[K, T]) updateEntry(key K, value T) {
-	// Get the node contains the entry
-	oldNode := c.entryNodes[key]
-
-	// Push new value to the recency list
-	c.entryRecency.Prepend(&entry[K, T]{key: key, value: value})
-	// Update the entries map
-	c.entryNodes[key] = c.entryRecency.Head()
+func (c *MRU[K, T]) miss(key K) {}
 
-	// Remove the node from the recency list
-	// If oldNode is tail then Pop, else just unlink
-	if oldNode.Right == nil {
-		_, _ = c.entryRecency.Pop()
-	} else {
-		oldNode.Unlink()
-	}
+func (c *MRU[K, T]) touch(e *entry[K, T]) {
+	// Mark it as recently used
+	c.moveToFront(e)
 }
 
-// Get the value associated with the given key argument.
-// If there is no such key returns [collection.ErrNotFound],
-// or if the cache is empty then returns [collection.ErrIsEmpty].
-// This marks the key as recently used.
-func (c *MRU[K, T]) Get(key K) (T, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+func (c *MRU[K, T]) add(e *entry[K, T]) {
+	// Mark it as recently used
+	c.pushFront(e)
+}
 
-	var zeroValue T
-	if c.entryRecency.Length() == 0 {
-		return zeroValue, collection.ErrIsEmpty
-	}
-	node, ok := c.entryNodes[key]
-	if !ok {
-		return zeroValue, collection.ErrNotFound
+func (c *MRU[K, T]) evict() *entry[K, T] {
+	entry := c.popFront()
+	if entry == nil {
+		// This should never happend
+		panic(
+			fmt.Errorf("something went very wrong; cannot drop MRU entry of cache with capacity of %d, entries length %d", c.cap, c.len()),
+		)
 	}
-
-	entry := node.Value
-	return entry.value, nil
+	return entry
 }
@@ -84,6 +84,142 @@ func TestMRU(t *testing.T) {
 	}
 }
 
+func TestMRUGetPromote(t *testing.T) {
+	MRU := cache.MustNewMRU[int, string](3)
+	MRU.Put(1, "A")
+	MRU.Put(2, "B")
+	MRU.Put(3, "C")
+
+	// Reading A marks it as most recently used, so A is evicted next
+	if _, err := MRU.Get(1); err != nil {
+		t.Errorf(testFailedMsg, "TestMRUGetPromote", "nil error", err)
+	}
+	MRU.Put(4, "D")
+	if _, err := MRU.Get(1); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestMRUGetPromote", collection.ErrNotFound, err)
+	}
+
+	// Reading B makes it the most recently used, so B is evicted instead of D
+	_, _ = MRU.Get(2)
+	MRU.Put(5, "E")
+	if _, err := MRU.Get(2); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestMRUGetPromote", collection.ErrNotFound, err)
+	}
+	for _, key := range []int{3, 4, 5} {
+		if _, err := MRU.Peek(key); err != nil {
+			t.Errorf(testFailedMsg, "TestMRUGetPromote", "nil error", err)
+		}
+	}
+}
+
+func TestMRUPeek(t *testing.T) {
+	MRU := cache.MustNewMRU[int, string](2)
+
+	// Should get is empty error
+	if _, err := MRU.Peek(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestMRUPeek", collection.ErrIsEmpty, err)
+	}
+
+	MRU.Put(1, "A")
+	MRU.Put(2, "B")
+
+	// Peeking A does not mark it as recently used, so B is still evicted
+	if val, err := MRU.Peek(1); err != nil {
+		t.Errorf(testFailedMsg, "TestMRUPeek", "nil error", err)
+	} else if val != "A" {
+		t.Errorf(testFailedMsg, "TestMRUPeek", "A", val)
+	}
+	MRU.Put(3, "C")
+	if _, err := MRU.Peek(2); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestMRUPeek", collection.ErrNotFound, err)
+	}
+	if _, err := MRU.Peek(1); err != nil {
+		t.Errorf(testFailedMsg, "TestMRUPeek", "nil error", err)
+	}
+}
+
+func TestMRUDelete(t *testing.T) {
+	MRU := cache.MustNewMRU[int, string](3)
+	if MRU.Delete(1) {
+		t.Errorf(testFailedMsg, "TestMRUDelete", false, true)
+	}
+
+	MRU.Put(1, "A")
+	MRU.Put(2, "B")
+	MRU.Put(3, "C")
+	MRU.Put(4, "D")
+	MRU.Put(5, "E")
+
+	// Delete the head, the tail and then the last remaining entry
+	for _, key := range []int{5, 1, 2} {
+		if !MRU.Delete(key) {
+			t.Errorf(testFailedMsg, "TestMRUDelete", true, false)
+		}
+		if MRU.Contains(key) {
+			t.Errorf(testFailedMsg, "TestMRUDelete", false, true)
+		}
+		if _, err := MRU.Get(key); err == nil {
+			t.Errorf(testFailedMsg, "TestMRUDelete", "error", err)
+		}
+	}
+	if MRU.Len() != 0 {
+		t.Errorf(testFailedMsg, "TestMRUDelete", 0, MRU.Len())
+	}
+
+	// The cache should still work after all entries are deleted
+	MRU.Put(6, "F")
+	MRU.Put(7, "G")
+	MRU.Put(8, "H")
+	MRU.Put(9, "I")
+	if MRU.Len() != 3 {
+		t.Errorf(testFailedMsg, "TestMRUDelete", 3, MRU.Len())
+	}
+	if !MRU.Contains(6) {
+		t.Errorf(testFailedMsg, "TestMRUDelete", true, false)
+	}
+}
+
+func TestMRUContains(t *testing.T) {
+	MRU := cache.MustNewMRU[int, string](2)
+	MRU.Put(1, "A")
+	MRU.Put(2, "B")
+
+	// Contains does not mark A as recently used
+	if !MRU.Contains(1) {
+		t.Errorf(testFailedMsg, "TestMRUContains", true, false)
+	}
+	MRU.Put(3, "C")
+	if MRU.Contains(2) {
+		t.Errorf(testFailedMsg, "TestMRUContains", false, true)
+	}
+}
+
+func TestMRULenCapClear(t *testing.T) {
+	MRU := cache.MustNewMRU[int, string](2)
+	if MRU.Cap() != 2 {
+		t.Errorf(testFailedMsg, "TestMRULenCapClear", 2, MRU.Cap())
+	}
+
+	MRU.Put(1, "A")
+	if MRU.Len() != 1 {
+		t.Errorf(testFailedMsg, "TestMRULenCapClear", 1, MRU.Len())
+	}
+	MRU.Put(1, "AA")
+	MRU.Put(2, "B")
+	MRU.Put(3, "C")
+	if MRU.Len() != 2 {
+		t.Errorf(testFailedMsg, "TestMRULenCapClear", 2, MRU.Len())
+	}
+
+	MRU.Clear()
+	if MRU.Len() != 0 {
+		t.Errorf(testFailedMsg, "TestMRULenCapClear", 0, MRU.Len())
+	}
+	if _, err := MRU.Get(3); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestMRULenCapClear", collection.ErrIsEmpty, err)
+	}
+}
+
 func TestMRURace(t *testing.T) {
 	var wg sync.WaitGroup
 	MRU := cache.MustNewMRU[int, int](randint(10, 50))
@@ -103,6 +239,31 @@ func TestMRURace(t *testing.T) {
 				_, _ = MRU.Get(randint(0, 100))
 			}
 		},
+
+		// Peek at the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = MRU.Peek(randint(0, 100))
+			}
+		},
+
+		// Delete from the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = MRU.Delete(randint(0, 100))
+			}
+		},
+
+		// Check if the cache contains a key
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = MRU.Contains(randint(0, 100))
+				_ = MRU.Len()
+			}
+		},
 	}
 
 	wg.Add(len(functions))
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// Option configures a cache on creation, it can be passed to any of the cache constructors.
+//
+//	lru, err := cache.NewLRU[string, []byte](1024, cache.WithTTL(time.Minute))
+type Option func(*options)
+
+type options struct {
+	// Default time-to-live of entries, zero means entries never expire.
+	ttl time.Duration
+	// Interval between runs of the janitor, zero means no janitor.
+	janitorInterval time.Duration
+	// Where the current time come from.
+	now func() time.Time
+	// How long errors of failed loads are kept, zero means they are not kept.
+	negativeTTL time.Duration
+	// Receives the events counted by the cache stats, nil if not set.
+	statsRecorder StatsRecorder
+	// Encodes and decodes snapshots of the cache.
+	codec Codec
+}
+
+func newOptions(opts []Option) (*options, error) {
+	o := &options{now: time.Now, codec: GobCodec{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.ttl < 0 {
+		return nil, fmt.Errorf("invalid specified ttl of %s", o.ttl)
+	}
+	if o.janitorInterval < 0 {
+		return nil, fmt.Errorf("invalid specified janitor interval of %s", o.janitorInterval)
+	}
+	if o.negativeTTL < 0 {
+		return nil, fmt.Errorf("invalid specified negative caching ttl of %s", o.negativeTTL)
+	}
+	if o.now == nil {
+		return nil, fmt.Errorf("clock function is required")
+	}
+	if o.codec == nil {
+		return nil, fmt.Errorf("codec is required")
+	}
+	return o, nil
+}
+
+// WithTTL sets the default time-to-live of entries put into the cache.
+// Entries put by Put will expire after ttl has passed,
+// a zero ttl means entries never expire, which is the default.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithJanitor starts a background goroutine that removes expired entries every interval.
+// Without a janitor expired entries are only removed when they are accessed or evicted.
+// The goroutine is stopped by calling Close on the cache.
+func WithJanitor(interval time.Duration) Option {
+	return func(o *options) {
+		o.janitorInterval = interval
+	}
+}
+
+// WithClock replaces [time.Now] as the source of the current time,
+// which is used to decide if entries are expired.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithNegativeCaching keeps the error of a failed load by GetOrLoad for ttl,
+// during which GetOrLoad returns the same error for the key without calling the loader again.
+// Putting or deleting the key forgets the error. A zero ttl means errors are not kept, which is the default.
+func WithNegativeCaching(ttl time.Duration) Option {
+	return func(o *options) {
+		o.negativeTTL = ttl
+	}
+}
+
+// WithStatsRecorder sets a [StatsRecorder] that receives the events counted by the cache stats,
+// in addition to the counters returned by Stats.
+func WithStatsRecorder(recorder StatsRecorder) Option {
+	return func(o *options) {
+		o.statsRecorder = recorder
+	}
+}
+
+// WithCodec sets the [Codec] used by SaveTo and LoadFrom to encode and decode entries of snapshots,
+// instead of [GobCodec].
+func WithCodec(codec Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
@@ -0,0 +1,262 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/internal"
+)
+
+// A cache that splits keys across several independent caches, called shards, by the hash of the keys.
+// Each shard has its own mutex, so operations on keys of different shards do not wait for each other,
+// which reduces lock contention when the cache is used by many goroutines.
+//
+// Every shard evicts its own entries, so the eviction policy only applies within a shard,
+// and the cache may evict an entry while other shards still have room.
+type Sharded[K comparable, T any] struct {
+	shards []Cache[K, T]
+	hash   func(key K) uint64
+}
+
+var _ internal.Cache[int, any] = (*Sharded[int, any])(nil)
+
+// [NewSharded] creates a new cache made of the given number of shards, each created by calling factory.
+// The hash function decides which shard a key belongs to, a nil hash uses a built-in hash function
+// that is fast for keys of basic types such as strings, integers and floats, and of named types defined on them.
+// Other keys, such as structs, are hashed from their Go-syntax representation, which is slow,
+// and tells -0 and +0 floats apart within them, so give a hash function for such keys.
+// It will return an error if shards is less than 1, factory is nil, or factory returns a nil cache.
+//
+//	sharded, err := cache.NewSharded(16, nil, func() cache.Cache[string, []byte] {
+//		return cache.MustNewLRU[string, []byte](1024)
+//	})
+func NewSharded[K comparable, T any](shards int, hash func(key K) uint64, factory func() Cache[K, T]) (*Sharded[K, T], error) {
+	if shards < 1 {
+		return nil, fmt.Errorf("failed to create sharded cache; cause by invalid specified number of shards of %d", shards)
+	}
+	if factory == nil {
+		return nil, fmt.Errorf("failed to create sharded cache; cause by factory is required")
+	}
+	if hash == nil {
+		hash = newKeyHasher[K]().hash
+	}
+
+	c := &Sharded[K, T]{
+		shards: make([]Cache[K, T], shards),
+		hash:   hash,
+	}
+	for i := range c.shards {
+		c.shards[i] = factory()
+		if c.shards[i] == nil {
+			return nil, fmt.Errorf("failed to create sharded cache; cause by factory returned a nil cache")
+		}
+	}
+	return c, nil
+}
+
+// Like [NewSharded] but will panic on error.
+func MustNewSharded[K comparable, T any](shards int, hash func(key K) uint64, factory func() Cache[K, T]) *Sharded[K, T] {
+	return collection.Must(
+		func() (*Sharded[K, T], error) {
+			return NewSharded(shards, hash, factory)
+		},
+	)
+}
+
+// Get the shard the key belongs to.
+func (c *Sharded[K, T]) shard(key K) Cache[K, T] {
+	return c.shards[c.hash(key)%uint64(len(c.shards))]
+}
+
+// Put a new value with an associated key into the shard of the key.
+func (c *Sharded[K, T]) Put(key K, value T) {
+	c.shard(key).Put(key, value)
+}
+
+// PutWithTTL is like Put, but the entry expires after the given ttl has passed.
+func (c *Sharded[K, T]) PutWithTTL(key K, value T, ttl time.Duration) {
+	c.shard(key).PutWithTTL(key, value, ttl)
+}
+
+// TryPut is like Put, but returns the error of the shard if the entry is not put.
+func (c *Sharded[K, T]) TryPut(key K, value T) error {
+	return c.shard(key).TryPut(key, value)
+}
+
+// TryPutWithTTL is like TryPut, but the entry expires after the given ttl has passed.
+func (c *Sharded[K, T]) TryPutWithTTL(key K, value T, ttl time.Duration) error {
+	return c.shard(key).TryPutWithTTL(key, value, ttl)
+}
+
+// Get the value associated with the given key from the shard of the key.
+// Get will return [collection.ErrNotFound] if there is no such key or the entry is expired,
+// or [collection.ErrIsEmpty] if the whole cache is empty.
+func (c *Sharded[K, T]) Get(key K) (T, error) {
+	value, err := c.shard(key).Get(key)
+	return value, c.notFound(err)
+}
+
+// Peek at the value associated with the given key argument,
+// like Get but does not mark the key as used.
+func (c *Sharded[K, T]) Peek(key K) (T, error) {
+	value, err := c.shard(key).Peek(key)
+	return value, c.notFound(err)
+}
+
+// A shard is empty does not mean the whole cache is empty.
+func (c *Sharded[K, T]) notFound(err error) error {
+	if errors.Is(err, collection.ErrIsEmpty) && c.Len() > 0 {
+		return collection.ErrNotFound
+	}
+	return err
+}
+
+// GetOrLoad gets the value associated with the given key like Get, or loads it into the shard of the key.
+// Loads of the same key are shared as they always happen in the same shard.
+func (c *Sharded[K, T]) GetOrLoad(ctx context.Context, key K, loader func(ctx context.Context, key K) (T, error)) (T, error) {
+	return c.shard(key).GetOrLoad(ctx, key, loader)
+}
+
+// Delete removes the entry associated with the given key from the shard of the key.
+func (c *Sharded[K, T]) Delete(key K) bool {
+	return c.shard(key).Delete(key)
+}
+
+// Contains reports whether the key is in its shard and not expired.
+func (c *Sharded[K, T]) Contains(key K) bool {
+	return c.shard(key).Contains(key)
+}
+
+// Len returns the number of entries currently in all shards.
+// The shards are counted one by one, so the result may not reflect any single moment.
+func (c *Sharded[K, T]) Len() int {
+	total := 0
+	for _, shard := range c.shards {
+		total += shard.Len()
+	}
+	return total
+}
+
+// Cap returns the sum of the capacity of all shards.
+func (c *Sharded[K, T]) Cap() int {
+	total := 0
+	for _, shard := range c.shards {
+		total += shard.Cap()
+	}
+	return total
+}
+
+// Cost returns the total cost of entries currently in all shards.
+// The shards are counted one by one, so the result may not reflect any single moment.
+func (c *Sharded[K, T]) Cost() int64 {
+	var total int64
+	for _, shard := range c.shards {
+		total += shard.Cost()
+	}
+	return total
+}
+
+// Clear removes all entries from every shard, one shard at a time.
+func (c *Sharded[K, T]) Clear() {
+	for _, shard := range c.shards {
+		shard.Clear()
+	}
+}
+
+// Close closes every shard, and returns the errors of the shards joined together.
+func (c *Sharded[K, T]) Close() error {
+	errs := make([]error, 0)
+	for _, shard := range c.shards {
+		if err := shard.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OnEvict sets the eviction hook of every shard.
+func (c *Sharded[K, T]) OnEvict(hook func(key K, value T, reason EvictReason)) {
+	for _, shard := range c.shards {
+		shard.OnEvict(hook)
+	}
+}
+
+// Stats returns the sum of the counters of every shard.
+func (c *Sharded[K, T]) Stats() Stats {
+	var total Stats
+	for _, shard := range c.shards {
+		s := shard.Stats()
+		total.Hits += s.Hits
+		total.Misses += s.Misses
+		total.Puts += s.Puts
+		total.Evictions += s.Evictions
+		total.Expirations += s.Expirations
+		total.LoadSuccesses += s.LoadSuccesses
+		total.LoadFailures += s.LoadFailures
+		total.TotalLoadTime += s.TotalLoadTime
+	}
+	return total
+}
+
+// ResetStats sets the counters of every shard back to zero.
+func (c *Sharded[K, T]) ResetStats() {
+	for _, shard := range c.shards {
+		shard.ResetStats()
+	}
+}
+
+// All returns an iterator of the keys and values in every shard, going shard by shard,
+// each from the most recently used entry to the least recently used one.
+// The order of use is only known within a shard, so entries of different shards are not ordered.
+// Each shard is iterated like the All method of the cache, on a snapshot taken when the iteration reaches it.
+func (c *Sharded[K, T]) All() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		for _, shard := range c.shards {
+			for key, val := range shard.All() {
+				if !yield(key, val) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Backward is like All, but goes through the shards in reverse order,
+// each from the least recently used entry to the most recently used one.
+func (c *Sharded[K, T]) Backward() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		for i := len(c.shards) - 1; i >= 0; i-- {
+			for key, val := range c.shards[i].Backward() {
+				if !yield(key, val) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Keys returns an iterator of the keys in every shard, in the same order as All.
+func (c *Sharded[K, T]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range c.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator of the values in every shard, in the same order as All.
+func (c *Sharded[K, T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, val := range c.All() {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package cache_test
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/trviph/collection/cache"
+)
+
+const (
+	benchCap  = 1 << 16
+	benchKeys = 1 << 17
+)
+
+// Run a mix of 90% Get and 10% Put on the cache from parallel goroutines.
+func benchmarkParallel(b *testing.B, c cache.Cache[int, int]) {
+	for key := range benchCap {
+		c.Put(key, key)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			key := r.Intn(benchKeys)
+			if r.Intn(10) == 0 {
+				c.Put(key, key)
+			} else {
+				_, _ = c.Get(key)
+			}
+		}
+	})
+}
+
+func BenchmarkLRUParallel(b *testing.B) {
+	benchmarkParallel(b, cache.MustNewLRU[int, int](benchCap))
+}
+
+func BenchmarkShardedLRUParallel(b *testing.B) {
+	shards := 4 * runtime.GOMAXPROCS(0)
+	benchmarkParallel(b, cache.MustNewSharded(shards, nil, func() cache.Cache[int, int] {
+		return cache.MustNewLRU[int, int](benchCap / shards)
+	}))
+}
+
+func BenchmarkTinyLFUParallel(b *testing.B) {
+	benchmarkParallel(b, cache.MustNewTinyLFU[int, int](benchCap))
+}
+
+func BenchmarkShardedTinyLFUParallel(b *testing.B) {
+	shards := 4 * runtime.GOMAXPROCS(0)
+	benchmarkParallel(b, cache.MustNewSharded(shards, nil, func() cache.Cache[int, int] {
+		return cache.MustNewTinyLFU[int, int](benchCap / shards)
+	}))
+}
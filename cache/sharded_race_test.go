@@ -0,0 +1,64 @@
+package cache_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/trviph/collection/cache"
+)
+
+func TestShardedRace(t *testing.T) {
+	var wg sync.WaitGroup
+	sharded := cache.MustNewSharded(randint(2, 16), nil, func() cache.Cache[int, int] {
+		return cache.MustNewLRU[int, int](randint(10, 50))
+	})
+	functions := []func(){
+		// Put to the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				sharded.Put(randint(0, 100), rand.Int())
+			}
+		},
+
+		// Get from the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = sharded.Get(randint(0, 100))
+			}
+		},
+
+		// Peek at the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = sharded.Peek(randint(0, 100))
+			}
+		},
+
+		// Delete from the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = sharded.Delete(randint(0, 100))
+			}
+		},
+
+		// Check if the cache contains a key
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = sharded.Contains(randint(0, 100))
+				_ = sharded.Len()
+			}
+		},
+	}
+
+	wg.Add(len(functions))
+	for _, f := range functions {
+		go f()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,168 @@
+package cache_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/cache"
+)
+
+// Put keys into shards by their value, so tests know which shard a key belongs to.
+func testShardHash(key int) uint64 {
+	return uint64(key)
+}
+
+func TestNewSharded(t *testing.T) {
+	factory := func() cache.Cache[int, int] {
+		return cache.MustNewLRU[int, int](1)
+	}
+	if _, err := cache.NewSharded(0, nil, factory); err == nil {
+		t.Errorf(testFailedMsg, "TestNewSharded", "error", err)
+	}
+	if _, err := cache.NewSharded[int, int](1, nil, nil); err == nil {
+		t.Errorf(testFailedMsg, "TestNewSharded", "error", err)
+	}
+	if _, err := cache.NewSharded(1, nil, func() cache.Cache[int, int] { return nil }); err == nil {
+		t.Errorf(testFailedMsg, "TestNewSharded", "error", err)
+	}
+	if _, err := cache.NewSharded(4, nil, factory); err != nil {
+		t.Errorf(testFailedMsg, "TestNewSharded", "nil error", err)
+	}
+}
+
+func TestMustNewSharded(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewSharded", "panic", r)
+		}
+	}()
+	_ = cache.MustNewSharded[int, int](1, nil, nil)
+}
+
+func TestSharded(t *testing.T) {
+	// Create a cache of 2 shards, each holding 2 values at maximum
+	sharded := cache.MustNewSharded(2, testShardHash, func() cache.Cache[int, string] {
+		return cache.MustNewLRU[int, string](2)
+	})
+	if sharded.Cap() != 4 {
+		t.Errorf(testFailedMsg, "TestSharded", 4, sharded.Cap())
+	}
+
+	// Should get is empty error
+	if _, err := sharded.Get(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestSharded", collection.ErrIsEmpty, err)
+	}
+
+	// Even keys go to the first shard, odd keys to the second
+	sharded.Put(0, "A")
+	sharded.Put(2, "B")
+	sharded.Put(4, "C")
+	if sharded.Contains(0) {
+		t.Errorf(testFailedMsg, "TestSharded", false, true)
+	}
+
+	// The second shard is empty, but the cache is not
+	if _, err := sharded.Get(1); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestSharded", collection.ErrNotFound, err)
+	}
+	if _, err := sharded.Peek(3); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestSharded", collection.ErrNotFound, err)
+	}
+
+	sharded.Put(1, "D")
+	if val, err := sharded.Get(1); err != nil || val != "D" {
+		t.Errorf(testFailedMsg, "TestSharded", "D", val)
+	}
+	if sharded.Len() != 3 || sharded.Cost() != 3 {
+		t.Errorf(testFailedMsg, "TestSharded", 3, sharded.Len())
+	}
+
+	if !sharded.Delete(2) {
+		t.Errorf(testFailedMsg, "TestSharded", true, false)
+	}
+	sharded.Clear()
+	if sharded.Len() != 0 {
+		t.Errorf(testFailedMsg, "TestSharded", 0, sharded.Len())
+	}
+	if err := sharded.Close(); err != nil {
+		t.Errorf(testFailedMsg, "TestSharded", "nil error", err)
+	}
+}
+
+func TestShardedOnEvict(t *testing.T) {
+	sharded := cache.MustNewSharded(4, testShardHash, func() cache.Cache[int, int] {
+		return cache.MustNewLRU[int, int](1)
+	})
+
+	evicted := 0
+	sharded.OnEvict(func(key int, value int, reason cache.EvictReason) {
+		evicted++
+	})
+	for key := range 10 {
+		sharded.Put(key, key)
+	}
+
+	// Every shard holds one entry, the others are evicted
+	if sharded.Len() != 4 {
+		t.Errorf(testFailedMsg, "TestShardedOnEvict", 4, sharded.Len())
+	}
+	if evicted != 6 {
+		t.Errorf(testFailedMsg, "TestShardedOnEvict", 6, evicted)
+	}
+}
+
+func TestShardedTryPut(t *testing.T) {
+	sharded := cache.MustNewSharded(2, testShardHash, func() cache.Cache[int, string] {
+		return cache.MustNewLRUWithCost(4, testCost)
+	})
+
+	var oversized *cache.OversizedError
+	if err := sharded.TryPut(1, "AAAAA"); !errors.As(err, &oversized) {
+		t.Errorf(testFailedMsg, "TestShardedTryPut", "oversized error", err)
+	}
+	if err := sharded.TryPut(1, "AAAA"); err != nil {
+		t.Errorf(testFailedMsg, "TestShardedTryPut", "nil error", err)
+	}
+	if err := sharded.TryPutWithTTL(2, "BB", 0); err != nil {
+		t.Errorf(testFailedMsg, "TestShardedTryPut", "nil error", err)
+	}
+	if sharded.Cost() != 6 {
+		t.Errorf(testFailedMsg, "TestShardedTryPut", 6, sharded.Cost())
+	}
+}
+
+func TestShardedHash(t *testing.T) {
+	type testID string
+	type testScore float64
+
+	// Equal keys go to the same shard with the built-in hash function, even with different signs of zero
+	floats := cache.MustNewSharded(16, nil, func() cache.Cache[float64, int] {
+		return cache.MustNewLRU[float64, int](1)
+	})
+	floats.Put(0, 1)
+	if val, err := floats.Get(math.Copysign(0, -1)); err != nil || val != 1 {
+		t.Errorf(testFailedMsg, "TestShardedHash", 1, val)
+	}
+	scores := cache.MustNewSharded(16, nil, func() cache.Cache[testScore, int] {
+		return cache.MustNewLRU[testScore, int](1)
+	})
+	scores.Put(testScore(math.Copysign(0, -1)), 1)
+	if val, err := scores.Get(0); err != nil || val != 1 {
+		t.Errorf(testFailedMsg, "TestShardedHash", 1, val)
+	}
+
+	// Keys of named types are hashed from the value of their underlying type, and are found again
+	ids := cache.MustNewSharded(16, nil, func() cache.Cache[testID, int] {
+		return cache.MustNewLRU[testID, int](16)
+	})
+	for i := range 16 {
+		ids.Put(testID(rune('A'+i)), i)
+	}
+	for i := range 16 {
+		if val, err := ids.Get(testID(rune('A' + i))); err != nil || val != i {
+			t.Errorf(testFailedMsg, "TestShardedHash", i, val)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/internal"
+)
+
+// A cache using the Segmented LRU eviction policy.
+// New entries are put in the probationary segment, and are moved to the protected segment when used again.
+// When the protected segment is full, its least recently used entry is moved back to the probationary segment.
+// Entries are only evicted from the probationary segment, unless it is empty.
+//
+// [Segmented LRU (SLRU)]: https://en.wikipedia.org/wiki/Cache_replacement_policies#Segmented_LRU_(SLRU)
+type SLRU[K comparable, T any] struct {
+	core[K, T]
+	slruList[K, T]
+}
+
+var _ internal.Cache[int, any] = (*SLRU[int, any])(nil)
+
+// [NewSLRU] creates a new cache with [SLRU] eviction policy.
+// It accepts cap as the first argument, specifying the maximum capacity of the cache,
+// the ratio of cap given to the protected segment, which is usually 0.8,
+// followed by any [Option] to configure the cache.
+// It will return an error if cap is less than 1, the ratio is not between 0 and 1, or an option is invalid.
+func NewSLRU[K comparable, T any](cap int, protectedRatio float64, opts ...Option) (*SLRU[K, T], error) {
+	if cap < 1 {
+		return nil, fmt.Errorf("failed to create slru; cause by invalid specified capacity")
+	}
+	return newSLRU[K, T](int64(cap), protectedRatio, nil, opts)
+}
+
+// Like [NewSLRU] but will panic on error.
+func MustNewSLRU[K comparable, T any](cap int, protectedRatio float64, opts ...Option) *SLRU[K, T] {
+	return collection.Must(
+		func() (*SLRU[K, T], error) {
+			return NewSLRU[K, T](cap, protectedRatio, opts...)
+		},
+	)
+}
+
+// [NewSLRUWithCost] is like [NewSLRU], but the capacity of the cache is measured by the cost of entries,
+// instead of the number of entries. It accepts maxCost as the first argument, specifying the maximum total cost of the cache,
+// the ratio of maxCost given to the protected segment, and cost computing the cost of an entry, which should not be negative.
+// It will return an error if maxCost is less than 1, the ratio is not between 0 and 1, cost is nil or an option is invalid.
+func NewSLRUWithCost[K comparable, T any](maxCost int64, protectedRatio float64, cost func(key K, value T) int64, opts ...Option) (*SLRU[K, T], error) {
+	if err := checkCost(maxCost, cost); err != nil {
+		return nil, fmt.Errorf("failed to create slru; cause by %w", err)
+	}
+	return newSLRU(maxCost, protectedRatio, cost, opts)
+}
+
+// Like [NewSLRUWithCost] but will panic on error.
+func MustNewSLRUWithCost[K comparable, T any](maxCost int64, protectedRatio float64, cost func(key K, value T) int64, opts ...Option) *SLRU[K, T] {
+	return collection.Must(
+		func() (*SLRU[K, T], error) {
+			return NewSLRUWithCost(maxCost, protectedRatio, cost, opts...)
+		},
+	)
+}
+
+func newSLRU[K comparable, T any](cap int64, protectedRatio float64, cost func(key K, value T) int64, opts []Option) (*SLRU[K, T], error) {
+	if protectedRatio < 0 || protectedRatio > 1 {
+		return nil, fmt.Errorf("failed to create slru; cause by invalid specified protected ratio of %v", protectedRatio)
+	}
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create slru; cause by %w", err)
+	}
+
+	c := &SLRU[K, T]{slruList: newSLRUList[K, T](int64(float64(cap) * protectedRatio))}
+	c.init(cap, cost, c, o)
+	return c, nil
+}
+
+func (c *SLRU[K, T]) miss(key K) {}
+
+// Drop the least recently used entry of the probationary segment, or of the protected segment if it is empty.
+func (c *SLRU[K, T]) evict() *entry[K, T] {
+	e := c.popBack()
+	if e == nil {
+		// This should never happend
+		panic(
+			fmt.Errorf("something went very wrong; cannot drop SLRU entry of cache with capacity of %d, entries length %d", c.cap, c.len()),
+		)
+	}
+	return e
+}
+
+// The probationary and protected segments of [SLRU], also used as the main area of [TinyLFU].
+type slruList[K comparable, T any] struct {
+	// Entries used only once since they were put, or demoted from the protected segment.
+	probation lruList[K, T]
+	// Entries used at least twice.
+	protected lruList[K, T]
+
+	// The maximum total cost of the protected segment.
+	protectedCap int64
+}
+
+func newSLRUList[K comparable, T any](protectedCap int64) slruList[K, T] {
+	return slruList[K, T]{
+		probation:    newLRUList[K, T](),
+		protected:    newLRUList[K, T](),
+		protectedCap: protectedCap,
+	}
+}
+
+func (l *slruList[K, T]) lookup(key K) (*entry[K, T], bool) {
+	if e, ok := l.protected.lookup(key); ok {
+		return e, true
+	}
+	return l.probation.lookup(key)
+}
+
+func (l *slruList[K, T]) touch(e *entry[K, T]) {
+	if _, ok := l.protected.lookup(e.key); ok {
+		l.protected.moveToFront(e)
+		return
+	}
+
+	// Promote the entry to the protected segment,
+	// if it is full then demote its least recently used entries.
+	l.probation.remove(e)
+	if e.cost > l.protectedCap {
+		l.probation.pushFront(e)
+		return
+	}
+	for l.protected.cost()+e.cost > l.protectedCap {
+		l.probation.pushFront(l.protected.popBack())
+	}
+	l.protected.pushFront(e)
+}
+
+// Add a new entry to the probationary segment.
+func (l *slruList[K, T]) add(e *entry[K, T]) {
+	l.probation.pushFront(e)
+}
+
+func (l *slruList[K, T]) remove(e *entry[K, T]) {
+	if _, ok := l.protected.lookup(e.key); ok {
+		l.protected.remove(e)
+		return
+	}
+	l.probation.remove(e)
+}
+
+// Return the entry that popBack would remove, nil if the segments are empty.
+func (l *slruList[K, T]) back() *entry[K, T] {
+	if e := l.probation.back(); e != nil {
+		return e
+	}
+	return l.protected.back()
+}
+
+// Remove and return the least recently used entry of the probationary segment,
+// or of the protected segment if it is empty. Return nil if both segments are empty.
+func (l *slruList[K, T]) popBack() *entry[K, T] {
+	if e := l.probation.popBack(); e != nil {
+		return e
+	}
+	return l.protected.popBack()
+}
+
+func (l *slruList[K, T]) len() int {
+	return l.probation.len() + l.protected.len()
+}
+
+func (l *slruList[K, T]) clear() {
+	l.probation.clear()
+	l.protected.clear()
+}
+
+// Iterate over entries of the protected segment then the probationary segment,
+// each from the most recently used to the least recently used.
+func (l *slruList[K, T]) entries() iter.Seq[*entry[K, T]] {
+	return func(yield func(*entry[K, T]) bool) {
+		for e := range l.protected.entries() {
+			if !yield(e) {
+				return
+			}
+		}
+		for e := range l.probation.entries() {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/cache"
+)
+
+func TestNewSLRU(t *testing.T) {
+	_, err := cache.NewSLRU[int, int](0, 0.8)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewSLRU", "error", err)
+	}
+	_, err = cache.NewSLRU[int, int](1, -0.1)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewSLRU", "error", err)
+	}
+	_, err = cache.NewSLRU[int, int](1, 1.1)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewSLRU", "error", err)
+	}
+	_, err = cache.NewSLRU[int, int](1, 0.8)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestNewSLRU", "nil error", err)
+	}
+}
+
+func TestMustNewSLRU(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewSLRU", "panic", r)
+		}
+	}()
+	_ = cache.MustNewSLRU[int, any](-1, 0.8)
+}
+
+func TestSLRU(t *testing.T) {
+	// Create a cache the only hold 4 values at maximum, 2 of them in the protected segment
+	slru, err := cache.NewSLRU[int, string](4, 0.5)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestSLRU", "nil error", err)
+	}
+
+	// Should get is empty error
+	if _, err := slru.Get(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestSLRU", collection.ErrIsEmpty, err)
+	}
+
+	for key, value := range []string{"A", "B", "C", "D"} {
+		slru.Put(key+1, value)
+	}
+
+	// A and B are protected, then C is protected which demotes A to probation
+	_, _ = slru.Get(1)
+	_, _ = slru.Get(2)
+	_, _ = slru.Get(3)
+
+	// D is the least recently used of probation, then A
+	slru.Put(5, "E")
+	if slru.Contains(4) {
+		t.Errorf(testFailedMsg, "TestSLRU", false, true)
+	}
+	slru.Put(6, "F")
+	if slru.Contains(1) {
+		t.Errorf(testFailedMsg, "TestSLRU", false, true)
+	}
+
+	// A scan of new entries does not flush the protected segment
+	for key := 7; key <= 20; key++ {
+		slru.Put(key, "scan")
+	}
+	for _, key := range []int{2, 3} {
+		if !slru.Contains(key) {
+			t.Errorf(testFailedMsg, "TestSLRU", true, false)
+		}
+	}
+	if slru.Len() != 4 {
+		t.Errorf(testFailedMsg, "TestSLRU", 4, slru.Len())
+	}
+}
+
+func TestSLRUWithoutProtected(t *testing.T) {
+	// Without a protected segment, SLRU behaves like LRU
+	slru := cache.MustNewSLRU[int, string](2, 0)
+	slru.Put(1, "A")
+	slru.Put(2, "B")
+	_, _ = slru.Get(1)
+	slru.Put(3, "C")
+	if slru.Contains(2) {
+		t.Errorf(testFailedMsg, "TestSLRUWithoutProtected", false, true)
+	}
+	if !slru.Contains(1) {
+		t.Errorf(testFailedMsg, "TestSLRUWithoutProtected", true, false)
+	}
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	ErrInvalidSnapshot     error = fmt.Errorf("invalid snapshot")
+	ErrUnsupportedSnapshot error = fmt.Errorf("unsupported snapshot version")
+)
+
+// Codec encodes and decodes the entries of a snapshot written by SaveTo and read by LoadFrom.
+// The default codec is [GobCodec], another one can be set by [WithCodec].
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder writes values to the writer it was created for, such as [gob.Encoder] or [encoding/json.Encoder].
+type Encoder interface {
+	Encode(v any) error
+}
+
+// Decoder reads values from the reader it was created for, such as [gob.Decoder] or [encoding/json.Decoder].
+type Decoder interface {
+	Decode(v any) error
+}
+
+// GobCodec is a [Codec] using [encoding/gob], which is the default codec of caches.
+// Keys or values of interface types must have their concrete types registered by [gob.Register].
+type GobCodec struct{}
+
+func (GobCodec) NewEncoder(w io.Writer) Encoder {
+	return gob.NewEncoder(w)
+}
+
+func (GobCodec) NewDecoder(r io.Reader) Decoder {
+	return gob.NewDecoder(r)
+}
+
+// Every snapshot starts with these bytes, followed by the version of the format as a big-endian uint16.
+var snapshotMagic = []byte("TVCACHE")
+
+// The version of the format written by SaveTo, it should be increased on every incompatible change of the format.
+const snapshotVersion uint16 = 1
+
+// The header of the entries of a snapshot, encoded by the codec.
+type snapshotHeader struct {
+	// The number of entries that follow.
+	Len int
+}
+
+// An entry of a snapshot, encoded by the codec.
+type snapshotEntry[K comparable, T any] struct {
+	Key   K
+	Value T
+	// The time left before the entry expires, zero means the entry never expires.
+	TTL time.Duration
+}
+
+// SaveTo writes a snapshot of the live entries of the cache to w, which can be read back by LoadFrom,
+// for example to restart with a warm cache. The snapshot starts with a versioned header,
+// followed by the entries encoded by the codec of the cache, from the least recently used to the most recently used,
+// with the time left before they expire.
+//
+// The entries are copied while holding the cache mutex, then encoded after releasing it,
+// so the cache can be used while saving, and changes made meanwhile are not saved.
+func (c *core[K, T]) SaveTo(w io.Writer) error {
+	entries := c.snapshot()
+	now := c.now()
+
+	header := make([]byte, len(snapshotMagic)+2)
+	copy(header, snapshotMagic)
+	binary.BigEndian.PutUint16(header[len(snapshotMagic):], snapshotVersion)
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("failed to save cache; cause by %w", err)
+	}
+
+	enc := c.codec.NewEncoder(w)
+	if err := enc.Encode(snapshotHeader{Len: len(entries)}); err != nil {
+		return fmt.Errorf("failed to save cache; cause by %w", err)
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		var ttl time.Duration
+		if !e.expiresAt.IsZero() {
+			// It may have expired since the snapshot, keep it for a moment so it is saved in order
+			ttl = max(e.expiresAt.Sub(now), time.Nanosecond)
+		}
+		if err := enc.Encode(snapshotEntry[K, T]{Key: e.key, Value: e.value, TTL: ttl}); err != nil {
+			return fmt.Errorf("failed to save cache; cause by %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadFrom reads a snapshot written by SaveTo from r, and puts its entries into the cache in the order they were used.
+// Only the entries and their recency order are restored, not the rest of the state of the eviction policy,
+// such as how often entries were used or the keys remembered after being evicted,
+// so only [LRU] and [MRU] evict the restored entries in the same order as before saving.
+// The entries expire after the time they had left when saved, the time between saving and loading is not counted.
+// Entries already in the cache are kept, but are less recently used than the restored ones.
+//
+// The snapshot must be written by a cache of the same key and value types, and read with the same codec.
+// It will return an error wrapping [ErrInvalidSnapshot] if r does not start with a snapshot header,
+// or [ErrUnsupportedSnapshot] if the snapshot is written in a format version this cache cannot read.
+// The whole snapshot is decoded before putting entries, so nothing is put if it fails to decode.
+func (c *core[K, T]) LoadFrom(r io.Reader) error {
+	header := make([]byte, len(snapshotMagic)+2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("failed to load cache; cause by %w: %w", ErrInvalidSnapshot, err)
+	}
+	if !bytes.Equal(header[:len(snapshotMagic)], snapshotMagic) {
+		return fmt.Errorf("failed to load cache; cause by %w", ErrInvalidSnapshot)
+	}
+	if version := binary.BigEndian.Uint16(header[len(snapshotMagic):]); version != snapshotVersion {
+		return fmt.Errorf("failed to load cache; cause by %w of %d", ErrUnsupportedSnapshot, version)
+	}
+
+	dec := c.codec.NewDecoder(r)
+	var h snapshotHeader
+	if err := dec.Decode(&h); err != nil {
+		return fmt.Errorf("failed to load cache; cause by %w", err)
+	}
+	if h.Len < 0 {
+		return fmt.Errorf("failed to load cache; cause by %w", ErrInvalidSnapshot)
+	}
+	entries := make([]snapshotEntry[K, T], 0, min(h.Len, 1024))
+	for range h.Len {
+		var e snapshotEntry[K, T]
+		if err := dec.Decode(&e); err != nil {
+			return fmt.Errorf("failed to load cache; cause by %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, e := range entries {
+		_ = c.put(e.Key, e.Value, e.TTL)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package cache_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/trviph/collection/cache"
+)
+
+// The caches that can be saved and loaded.
+type testSnapshotCache interface {
+	SaveTo(w io.Writer) error
+	LoadFrom(r io.Reader) error
+}
+
+// A codec using encoding/json.
+type testJSONCodec struct{}
+
+func (testJSONCodec) NewEncoder(w io.Writer) cache.Encoder {
+	return json.NewEncoder(w)
+}
+
+func (testJSONCodec) NewDecoder(r io.Reader) cache.Decoder {
+	return json.NewDecoder(r)
+}
+
+func TestSaveLoad(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		for _, codec := range []cache.Codec{cache.GobCodec{}, testJSONCodec{}} {
+			clock := newFakeClock()
+			c := newCache(3, cache.WithClock(clock.Now), cache.WithCodec(codec))
+			saved, ok := c.(testSnapshotCache)
+			if !ok {
+				continue
+			}
+			c.Put(1, "A")
+			c.PutWithTTL(2, "B", time.Minute)
+			c.Put(3, "C")
+			_, _ = c.Get(1)
+			clock.Advance(30 * time.Second)
+
+			var buf bytes.Buffer
+			if err := saved.SaveTo(&buf); err != nil {
+				t.Fatalf(testFailedMsg, "TestSaveLoad "+name, "nil error", err)
+			}
+
+			// The restored cache has the same entries in the same order
+			restoredClock := newFakeClock()
+			restored := newCache(3, cache.WithClock(restoredClock.Now), cache.WithCodec(codec))
+			if err := restored.(testSnapshotCache).LoadFrom(&buf); err != nil {
+				t.Fatalf(testFailedMsg, "TestSaveLoad "+name, "nil error", err)
+			}
+			want, got := slices.Collect(c.Keys()), slices.Collect(restored.Keys())
+			if !slices.Equal(want, got) {
+				t.Errorf(testFailedMsg, "TestSaveLoad "+name, want, got)
+			}
+			if val, err := restored.Peek(3); err != nil || val != "C" {
+				t.Errorf(testFailedMsg, "TestSaveLoad "+name, "C", val)
+			}
+
+			// The entry with a TTL expires after the time it had left
+			restoredClock.Advance(30*time.Second - time.Nanosecond)
+			if !restored.Contains(2) {
+				t.Errorf(testFailedMsg, "TestSaveLoad "+name, true, false)
+			}
+			restoredClock.Advance(time.Nanosecond)
+			if restored.Contains(2) {
+				t.Errorf(testFailedMsg, "TestSaveLoad "+name, false, true)
+			}
+		}
+	}
+}
+
+func TestLoadRecencyOrder(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		c := newCache(4)
+		saved, ok := c.(testSnapshotCache)
+		if !ok {
+			continue
+		}
+		// The recency order differs from the order of frequency, which is not restored
+		for key := range 4 {
+			c.Put(key, fmt.Sprint(key))
+		}
+		for range 3 {
+			_, _ = c.Get(0)
+		}
+		_, _ = c.Get(2)
+
+		var buf bytes.Buffer
+		if err := saved.SaveTo(&buf); err != nil {
+			t.Fatalf(testFailedMsg, "TestLoadRecencyOrder "+name, "nil error", err)
+		}
+		restored := newCache(4)
+		if err := restored.(testSnapshotCache).LoadFrom(&buf); err != nil {
+			t.Fatalf(testFailedMsg, "TestLoadRecencyOrder "+name, "nil error", err)
+		}
+
+		// The restored cache has the same entries, from the most recently used to the least
+		wantKeys, gotKeys := slices.Collect(c.Keys()), slices.Collect(restored.Keys())
+		if !slices.Equal(wantKeys, []int{2, 0, 3, 1}) || !slices.Equal(wantKeys, gotKeys) {
+			t.Errorf(testFailedMsg, "TestLoadRecencyOrder "+name, wantKeys, gotKeys)
+		}
+		wantValues, gotValues := slices.Collect(c.Values()), slices.Collect(restored.Values())
+		if !slices.Equal(wantValues, gotValues) {
+			t.Errorf(testFailedMsg, "TestLoadRecencyOrder "+name, wantValues, gotValues)
+		}
+	}
+}
+
+func TestLoadEvictionOrder(t *testing.T) {
+	lru := cache.MustNewLRU[int, string](3)
+	for key := range 3 {
+		lru.Put(key, "A")
+	}
+	_, _ = lru.Get(0)
+
+	var buf bytes.Buffer
+	if err := lru.SaveTo(&buf); err != nil {
+		t.Fatalf(testFailedMsg, "TestLoadEvictionOrder", "nil error", err)
+	}
+	restored := cache.MustNewLRU[int, string](3)
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatalf(testFailedMsg, "TestLoadEvictionOrder", "nil error", err)
+	}
+
+	// 1 was the least recently used before saving, so it is evicted first
+	restored.Put(3, "B")
+	if restored.Contains(1) {
+		t.Errorf(testFailedMsg, "TestLoadEvictionOrder", false, true)
+	}
+	restored.Put(4, "B")
+	if restored.Contains(2) {
+		t.Errorf(testFailedMsg, "TestLoadEvictionOrder", false, true)
+	}
+
+	mru := cache.MustNewMRU[int, string](3)
+	for key := range 3 {
+		mru.Put(key, "A")
+	}
+	_, _ = mru.Get(1)
+
+	buf.Reset()
+	if err := mru.SaveTo(&buf); err != nil {
+		t.Fatalf(testFailedMsg, "TestLoadEvictionOrder", "nil error", err)
+	}
+	restoredMRU := cache.MustNewMRU[int, string](3)
+	if err := restoredMRU.LoadFrom(&buf); err != nil {
+		t.Fatalf(testFailedMsg, "TestLoadEvictionOrder", "nil error", err)
+	}
+
+	// 1 was the most recently used before saving, so it is evicted first
+	restoredMRU.Put(3, "B")
+	if restoredMRU.Contains(1) {
+		t.Errorf(testFailedMsg, "TestLoadEvictionOrder", false, true)
+	}
+	for _, key := range []int{0, 2, 3} {
+		if !restoredMRU.Contains(key) {
+			t.Errorf(testFailedMsg, "TestLoadEvictionOrder", true, false)
+		}
+	}
+}
+
+func TestLoadFromInvalid(t *testing.T) {
+	lru := cache.MustNewLRU[int, string](3)
+	lru.Put(1, "A")
+	var buf bytes.Buffer
+	if err := lru.SaveTo(&buf); err != nil {
+		t.Fatalf(testFailedMsg, "TestLoadFromInvalid", "nil error", err)
+	}
+	snapshot := buf.Bytes()
+
+	restored := cache.MustNewLRU[int, string](3)
+	if err := restored.LoadFrom(bytes.NewReader([]byte("TV"))); !errors.Is(err, cache.ErrInvalidSnapshot) {
+		t.Errorf(testFailedMsg, "TestLoadFromInvalid", cache.ErrInvalidSnapshot, err)
+	}
+	if err := restored.LoadFrom(bytes.NewReader([]byte("NOTACACHESNAPSHOT"))); !errors.Is(err, cache.ErrInvalidSnapshot) {
+		t.Errorf(testFailedMsg, "TestLoadFromInvalid", cache.ErrInvalidSnapshot, err)
+	}
+
+	// A snapshot of a later version of the format
+	future := slices.Clone(snapshot)
+	future[8]++
+	if err := restored.LoadFrom(bytes.NewReader(future)); !errors.Is(err, cache.ErrUnsupportedSnapshot) {
+		t.Errorf(testFailedMsg, "TestLoadFromInvalid", cache.ErrUnsupportedSnapshot, err)
+	}
+
+	// A truncated snapshot puts nothing
+	if err := restored.LoadFrom(bytes.NewReader(snapshot[:len(snapshot)-2])); err == nil {
+		t.Errorf(testFailedMsg, "TestLoadFromInvalid", "error", err)
+	}
+
+	// A snapshot of other types
+	other := cache.MustNewLRU[string, int](3)
+	if err := other.LoadFrom(bytes.NewReader(snapshot)); err == nil {
+		t.Errorf(testFailedMsg, "TestLoadFromInvalid", "error", err)
+	}
+	if restored.Len() != 0 || other.Len() != 0 {
+		t.Errorf(testFailedMsg, "TestLoadFromInvalid", 0, restored.Len())
+	}
+}
+
+type testFailingWriter struct{}
+
+func (testFailingWriter) Write(p []byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestSaveToError(t *testing.T) {
+	lru := cache.MustNewLRU[int, string](3)
+	if err := lru.SaveTo(testFailingWriter{}); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf(testFailedMsg, "TestSaveToError", io.ErrClosedPipe, err)
+	}
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the counters of a cache, returned by Stats.
+type Stats struct {
+	// The number of Get and GetOrLoad calls that found the key.
+	Hits uint64
+	// The number of Get and GetOrLoad calls that did not find the key.
+	Misses uint64
+	// The number of entries put into the cache, including updates and loaded entries.
+	Puts uint64
+	// The number of entries dropped by the eviction policy to make room for other entries.
+	Evictions uint64
+	// The number of entries removed because their TTL has passed.
+	Expirations uint64
+	// The number of loads by GetOrLoad that succeeded or failed.
+	LoadSuccesses, LoadFailures uint64
+	// The total time spent loading values by GetOrLoad.
+	TotalLoadTime time.Duration
+}
+
+// HitRatio returns the ratio of hits over all calls that looked up a key, or 0 if there is none.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// AverageLoadTime returns the average time spent on a load, or 0 if there is none.
+func (s Stats) AverageLoadTime() time.Duration {
+	total := s.LoadSuccesses + s.LoadFailures
+	if total == 0 {
+		return 0
+	}
+	return s.TotalLoadTime / time.Duration(total)
+}
+
+// StatsRecorder receives the events counted by the [Stats] of a cache as they happen,
+// so they can be forwarded to another metrics system. It is set by [WithStatsRecorder].
+//
+// The methods may be called while the cache mutex is held, and from many goroutines concurrently,
+// so they should be fast, thread-safe, and must not call back into the cache.
+type StatsRecorder interface {
+	RecordHit()
+	RecordMiss()
+	RecordPut()
+	// Called for every entry removed from the cache, with the reason it was removed.
+	RecordEviction(reason EvictReason)
+	RecordLoadSuccess(loadTime time.Duration)
+	RecordLoadFailure(loadTime time.Duration)
+}
+
+// The counters of a cache, they are atomics so they can be read without holding the cache mutex.
+type stats struct {
+	hits, misses, puts, evictions, expirations atomic.Uint64
+	loadSuccesses, loadFailures                atomic.Uint64
+	totalLoadTime                              atomic.Int64
+
+	// Also receives every event, nil if not set.
+	recorder StatsRecorder
+}
+
+func (s *stats) recordHit() {
+	s.hits.Add(1)
+	if s.recorder != nil {
+		s.recorder.RecordHit()
+	}
+}
+
+func (s *stats) recordMiss() {
+	s.misses.Add(1)
+	if s.recorder != nil {
+		s.recorder.RecordMiss()
+	}
+}
+
+func (s *stats) recordPut() {
+	s.puts.Add(1)
+	if s.recorder != nil {
+		s.recorder.RecordPut()
+	}
+}
+
+func (s *stats) recordEviction(reason EvictReason) {
+	switch reason {
+	case EvictCapacity:
+		s.evictions.Add(1)
+	case EvictExpired:
+		s.expirations.Add(1)
+	}
+	if s.recorder != nil {
+		s.recorder.RecordEviction(reason)
+	}
+}
+
+func (s *stats) recordLoad(loadTime time.Duration, err error) {
+	s.totalLoadTime.Add(int64(loadTime))
+	if err != nil {
+		s.loadFailures.Add(1)
+		if s.recorder != nil {
+			s.recorder.RecordLoadFailure(loadTime)
+		}
+		return
+	}
+	s.loadSuccesses.Add(1)
+	if s.recorder != nil {
+		s.recorder.RecordLoadSuccess(loadTime)
+	}
+}
+
+func (s *stats) snapshot() Stats {
+	return Stats{
+		Hits:          s.hits.Load(),
+		Misses:        s.misses.Load(),
+		Puts:          s.puts.Load(),
+		Evictions:     s.evictions.Load(),
+		Expirations:   s.expirations.Load(),
+		LoadSuccesses: s.loadSuccesses.Load(),
+		LoadFailures:  s.loadFailures.Load(),
+		TotalLoadTime: time.Duration(s.totalLoadTime.Load()),
+	}
+}
+
+func (s *stats) reset() {
+	s.hits.Store(0)
+	s.misses.Store(0)
+	s.puts.Store(0)
+	s.evictions.Store(0)
+	s.expirations.Store(0)
+	s.loadSuccesses.Store(0)
+	s.loadFailures.Store(0)
+	s.totalLoadTime.Store(0)
+}
@@ -0,0 +1,214 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/trviph/collection/cache"
+)
+
+// A recorder that counts the events it receives.
+type testRecorder struct {
+	mu                          sync.Mutex
+	hits, misses, puts          int
+	evictions                   map[cache.EvictReason]int
+	loadSuccesses, loadFailures int
+}
+
+func newTestRecorder() *testRecorder {
+	return &testRecorder{evictions: make(map[cache.EvictReason]int)}
+}
+
+func (r *testRecorder) RecordHit() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hits++
+}
+
+func (r *testRecorder) RecordMiss() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.misses++
+}
+
+func (r *testRecorder) RecordPut() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.puts++
+}
+
+func (r *testRecorder) RecordEviction(reason cache.EvictReason) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evictions[reason]++
+}
+
+func (r *testRecorder) RecordLoadSuccess(loadTime time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loadSuccesses++
+}
+
+func (r *testRecorder) RecordLoadFailure(loadTime time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loadFailures++
+}
+
+func TestStats(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		clock := newFakeClock()
+		recorder := newTestRecorder()
+		c := newCache(2, cache.WithClock(clock.Now), cache.WithStatsRecorder(recorder))
+
+		c.Put(1, "A")
+		c.Put(2, "B")
+		_, _ = c.Get(1)
+		_, _ = c.Get(3)
+		// Evict one of the entries by capacity
+		c.Put(3, "C")
+		c.PutWithTTL(4, "D", time.Second)
+		clock.Advance(time.Second)
+		_, _ = c.Get(4)
+		_, _ = c.GetOrLoad(context.Background(), 5, func(ctx context.Context, key int) (string, error) {
+			return "E", nil
+		})
+		_, _ = c.GetOrLoad(context.Background(), 6, func(ctx context.Context, key int) (string, error) {
+			return "", errors.New("load failed")
+		})
+
+		want := cache.Stats{
+			Hits:          1,
+			Misses:        4,
+			Puts:          5,
+			Evictions:     2,
+			Expirations:   1,
+			LoadSuccesses: 1,
+			LoadFailures:  1,
+		}
+		got := c.Stats()
+		got.TotalLoadTime = 0
+		if got != want {
+			t.Errorf(testFailedMsg, "TestStats "+name, want, got)
+		}
+		if got.HitRatio() != 0.2 {
+			t.Errorf(testFailedMsg, "TestStats "+name, 0.2, got.HitRatio())
+		}
+
+		// The recorder receives the same events
+		recorder.mu.Lock()
+		if recorder.hits != 1 || recorder.misses != 4 || recorder.puts != 5 {
+			t.Errorf(testFailedMsg, "TestStats "+name, "1 hit, 4 misses and 5 puts", recorder)
+		}
+		if recorder.evictions[cache.EvictCapacity] != 2 || recorder.evictions[cache.EvictExpired] != 1 {
+			t.Errorf(testFailedMsg, "TestStats "+name, "2 evictions and 1 expiration", recorder.evictions)
+		}
+		if recorder.loadSuccesses != 1 || recorder.loadFailures != 1 {
+			t.Errorf(testFailedMsg, "TestStats "+name, "1 load success and 1 load failure", recorder)
+		}
+		recorder.mu.Unlock()
+
+		// Replacing and deleting entries are not evictions
+		c.Put(5, "EE")
+		c.Delete(5)
+		if s := c.Stats(); s.Evictions != 2 || s.Puts != 6 {
+			t.Errorf(testFailedMsg, "TestStats "+name, "2 evictions and 6 puts", s)
+		}
+		recorder.mu.Lock()
+		if recorder.evictions[cache.EvictReplaced] != 1 || recorder.evictions[cache.EvictDeleted] != 1 {
+			t.Errorf(testFailedMsg, "TestStats "+name, "1 replacement and 1 deletion", recorder.evictions)
+		}
+		recorder.mu.Unlock()
+
+		c.ResetStats()
+		if c.Stats() != (cache.Stats{}) {
+			t.Errorf(testFailedMsg, "TestStats "+name, cache.Stats{}, c.Stats())
+		}
+	}
+}
+
+func TestStatsClear(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		clock := newFakeClock()
+		recorder := newTestRecorder()
+		c := newCache(4, cache.WithClock(clock.Now), cache.WithStatsRecorder(recorder))
+
+		// Clearing is recorded even if there is no eviction hook, expired entries are recorded as expirations
+		c.Put(1, "A")
+		c.Put(2, "B")
+		c.PutWithTTL(3, "C", time.Second)
+		clock.Advance(time.Second)
+		c.Clear()
+
+		if s := c.Stats(); s.Evictions != 0 || s.Expirations != 1 {
+			t.Errorf(testFailedMsg, "TestStatsClear "+name, "0 evictions and 1 expiration", s)
+		}
+		recorder.mu.Lock()
+		if recorder.evictions[cache.EvictDeleted] != 2 || recorder.evictions[cache.EvictExpired] != 1 {
+			t.Errorf(testFailedMsg, "TestStatsClear "+name, "2 deletions and 1 expiration", recorder.evictions)
+		}
+		recorder.mu.Unlock()
+	}
+}
+
+func TestStatsRatio(t *testing.T) {
+	var s cache.Stats
+	if s.HitRatio() != 0 {
+		t.Errorf(testFailedMsg, "TestStatsRatio", 0, s.HitRatio())
+	}
+	if s.AverageLoadTime() != 0 {
+		t.Errorf(testFailedMsg, "TestStatsRatio", 0, s.AverageLoadTime())
+	}
+
+	s = cache.Stats{Hits: 3, Misses: 1, LoadSuccesses: 3, LoadFailures: 1, TotalLoadTime: time.Second}
+	if s.HitRatio() != 0.75 {
+		t.Errorf(testFailedMsg, "TestStatsRatio", 0.75, s.HitRatio())
+	}
+	if s.AverageLoadTime() != 250*time.Millisecond {
+		t.Errorf(testFailedMsg, "TestStatsRatio", 250*time.Millisecond, s.AverageLoadTime())
+	}
+}
+
+func TestStatsRace(t *testing.T) {
+	for name, newCache := range testCacheConstructors {
+		var wg sync.WaitGroup
+		c := newCache(randint(10, 50), cache.WithStatsRecorder(newTestRecorder()))
+		functions := []func(){
+			// Put to the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					c.Put(randint(0, 100), name)
+				}
+			},
+
+			// Get from the cache
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_, _ = c.Get(randint(0, 100))
+				}
+			},
+
+			// Read and reset the stats
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_ = c.Stats().HitRatio()
+					if i%100 == 0 {
+						c.ResetStats()
+					}
+				}
+			},
+		}
+
+		wg.Add(len(functions))
+		for _, f := range functions {
+			go f()
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,379 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/internal"
+)
+
+// Implemented by the caches of this package, so they can be the first tier of a [Tiered] cache.
+type tier[K comparable, T any] interface {
+	// Set a hook that is called for every entry dropped by the eviction policy, after the cache mutex is released.
+	// It returns false if the cache cannot pass the entries it drops to the hook.
+	setSpill(hook func(key K, value T, expiresAt time.Time)) bool
+	// Put an entry moved up from a lower tier, unless the key is already in the cache.
+	// It returns false if the entry is not put.
+	promote(key K, value T, expiresAt time.Time) bool
+}
+
+type tierCache[K comparable, T any] interface {
+	Cache[K, T]
+	tier[K, T]
+}
+
+func (c *core[K, T]) setSpill(hook func(key K, value T, expiresAt time.Time)) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.spill = hook
+	return true
+}
+
+func (c *core[K, T]) promote(key K, value T, expiresAt time.Time) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.now()
+	if e, ok := c.policy.lookup(key); ok && !e.expired(now) {
+		return false
+	}
+	var ttl time.Duration
+	if !expiresAt.IsZero() {
+		ttl = expiresAt.Sub(now)
+		if ttl <= 0 {
+			return false
+		}
+	}
+	return c.put(key, value, ttl) == nil
+}
+
+func (c *Sharded[K, T]) setSpill(hook func(key K, value T, expiresAt time.Time)) bool {
+	for _, shard := range c.shards {
+		if _, ok := shard.(tier[K, T]); !ok {
+			return false
+		}
+	}
+	for _, shard := range c.shards {
+		shard.(tier[K, T]).setSpill(hook)
+	}
+	return true
+}
+
+func (c *Sharded[K, T]) promote(key K, value T, expiresAt time.Time) bool {
+	return c.shard(key).(tier[K, T]).promote(key, value, expiresAt)
+}
+
+// A cache made of two tiers: an in-memory cache of any eviction policy of this package as the first tier,
+// and a log file on disk as the second tier, to hold more entries than the memory can.
+//
+// Entries dropped by the eviction policy of the first tier are written to the second tier instead of being lost,
+// along with the moment they expire. Getting a key that is only in the second tier moves its entry back
+// into the first tier, which may in turn drop other entries down to the disk.
+//
+// The second tier is an append-only log with an in-memory index of where the latest record of every key is.
+// Moving, replacing or deleting an entry leaves its previous record in the log as garbage, which is removed by compaction,
+// rewriting the live records to a new log file. Compaction runs on its own once the garbage is larger than 1 MiB
+// and larger than the live records, or when Compact is called.
+// Records are not synced to the disk when written, a record torn by a crash is dropped when the log is opened again.
+//
+// The tiers are not updated together atomically, an entry dropped by the first tier while it is deleted
+// may still be written to the second tier.
+type Tiered[K comparable, T any] struct {
+	l1 tierCache[K, T]
+	l2 *diskLog[K, T]
+}
+
+var _ internal.Cache[int, any] = (*Tiered[int, any])(nil)
+
+// [NewTiered] creates a new tiered cache with the given cache as the first tier,
+// and a log file named tiered.log in the directory dir as the second tier.
+// The directory is created if it does not exist, and should not be used by other tiered caches at the same time.
+// The entries left in the log by a previous tiered cache are kept in the second tier.
+//
+// Keys and values are written to the log by the codec given by [WithCodec], and expire by the clock given by [WithClock],
+// which should be the same as the clock of the first tier. The other options do not apply to the second tier.
+// It will return an error if l1 is nil or not a cache of this package, if dir is empty,
+// or if the log cannot be opened.
+//
+//	tiered, err := cache.NewTiered(cache.MustNewLRU[string, []byte](1024), "/var/cache/app")
+func NewTiered[K comparable, T any](l1 Cache[K, T], dir string, opts ...Option) (*Tiered[K, T], error) {
+	if l1 == nil {
+		return nil, fmt.Errorf("failed to create tiered cache; cause by first tier is required")
+	}
+	first, ok := l1.(tierCache[K, T])
+	if !ok {
+		return nil, fmt.Errorf("failed to create tiered cache; cause by first tier of %T is not a cache of this package", l1)
+	}
+	if dir == "" {
+		return nil, fmt.Errorf("failed to create tiered cache; cause by directory is required")
+	}
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tiered cache; cause by %w", err)
+	}
+	l2, err := openDiskLog[K, T](dir, o.codec, o.now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tiered cache; cause by %w", err)
+	}
+
+	c := &Tiered[K, T]{l1: first, l2: l2}
+	if !first.setSpill(func(key K, value T, expiresAt time.Time) {
+		// The entry is lost if it cannot be written, as if there were no second tier
+		_ = c.l2.put(key, value, expiresAt)
+	}) {
+		_ = l2.close()
+		return nil, fmt.Errorf("failed to create tiered cache; cause by first tier of %T is not a cache of this package", l1)
+	}
+	return c, nil
+}
+
+// Like [NewTiered] but will panic on error.
+func MustNewTiered[K comparable, T any](l1 Cache[K, T], dir string, opts ...Option) *Tiered[K, T] {
+	return collection.Must(
+		func() (*Tiered[K, T], error) {
+			return NewTiered(l1, dir, opts...)
+		},
+	)
+}
+
+// Put a new value with an associated key into the first tier, replacing the entry of the key in the second tier if there is any.
+func (c *Tiered[K, T]) Put(key K, value T) {
+	c.l1.Put(key, value)
+	_, _ = c.l2.delete(key)
+}
+
+// PutWithTTL is like Put, but the entry expires after the given ttl has passed.
+func (c *Tiered[K, T]) PutWithTTL(key K, value T, ttl time.Duration) {
+	c.l1.PutWithTTL(key, value, ttl)
+	_, _ = c.l2.delete(key)
+}
+
+// TryPut is like Put, but returns the error of the first tier if the entry is not put.
+func (c *Tiered[K, T]) TryPut(key K, value T) error {
+	err := c.l1.TryPut(key, value)
+	_, _ = c.l2.delete(key)
+	return err
+}
+
+// TryPutWithTTL is like TryPut, but the entry expires after the given ttl has passed.
+func (c *Tiered[K, T]) TryPutWithTTL(key K, value T, ttl time.Duration) error {
+	err := c.l1.TryPutWithTTL(key, value, ttl)
+	_, _ = c.l2.delete(key)
+	return err
+}
+
+// Get the value associated with the given key from the first tier, or else from the second tier,
+// in which case the entry is moved into the first tier.
+// Get will return [collection.ErrNotFound] if there is no such key or the entry is expired,
+// [collection.ErrIsEmpty] if the whole cache is empty, or the error of reading the disk.
+// This marks the key as used.
+func (c *Tiered[K, T]) Get(key K) (T, error) {
+	value, err := c.l1.Get(key)
+	if err == nil {
+		return value, nil
+	}
+	value, ok, diskErr := c.fromDisk(key)
+	if diskErr != nil {
+		return value, diskErr
+	}
+	if !ok {
+		return value, c.notFound(err)
+	}
+	return value, nil
+}
+
+// Take the entry of a key from the second tier and move it into the first tier.
+func (c *Tiered[K, T]) fromDisk(key K) (T, bool, error) {
+	value, expiresAt, ok, err := c.l2.take(key)
+	if err != nil {
+		return value, false, fmt.Errorf("failed to read from disk; cause by %w", err)
+	}
+	if !ok {
+		return value, false, nil
+	}
+	if !c.l1.promote(key, value, expiresAt) {
+		// The key was put meanwhile, its new value wins
+		if current, err := c.l1.Peek(key); err == nil {
+			return current, true, nil
+		}
+		// Or the entry does not fit in the first tier, keep it on the disk
+		_ = c.l2.put(key, value, expiresAt)
+	}
+	return value, true, nil
+}
+
+// Peek at the value associated with the given key argument in both tiers,
+// like Get but does not mark the key as used, nor move the entry into the first tier.
+func (c *Tiered[K, T]) Peek(key K) (T, error) {
+	value, err := c.l1.Peek(key)
+	if err == nil {
+		return value, nil
+	}
+	value, ok, diskErr := c.l2.get(key)
+	if diskErr != nil {
+		return value, fmt.Errorf("failed to read from disk; cause by %w", diskErr)
+	}
+	if !ok {
+		return value, c.notFound(err)
+	}
+	return value, nil
+}
+
+// The first tier is empty does not mean the whole cache is empty.
+func (c *Tiered[K, T]) notFound(err error) error {
+	if errors.Is(err, collection.ErrIsEmpty) && c.Len() > 0 {
+		return collection.ErrNotFound
+	}
+	return err
+}
+
+// GetOrLoad gets the value associated with the given key like Get, or loads it into the first tier.
+// The loader is only called if the key is in neither tier.
+func (c *Tiered[K, T]) GetOrLoad(ctx context.Context, key K, loader func(ctx context.Context, key K) (T, error)) (T, error) {
+	if !c.l1.Contains(key) {
+		if _, _, err := c.fromDisk(key); err != nil {
+			var zeroValue T
+			return zeroValue, err
+		}
+	}
+	return c.l1.GetOrLoad(ctx, key, loader)
+}
+
+// Delete removes the entry associated with the given key from both tiers.
+func (c *Tiered[K, T]) Delete(key K) bool {
+	deleted := c.l1.Delete(key)
+	onDisk, _ := c.l2.delete(key)
+	return deleted || onDisk
+}
+
+// Contains reports whether the key is in either tier and not expired.
+func (c *Tiered[K, T]) Contains(key K) bool {
+	return c.l1.Contains(key) || c.l2.contains(key)
+}
+
+// Len returns the number of entries currently in both tiers.
+func (c *Tiered[K, T]) Len() int {
+	return c.l1.Len() + c.l2.len()
+}
+
+// Cap returns the capacity of the first tier, the second tier is only bounded by the disk.
+func (c *Tiered[K, T]) Cap() int {
+	return c.l1.Cap()
+}
+
+// Cost returns the total cost of entries currently in the first tier.
+func (c *Tiered[K, T]) Cost() int64 {
+	return c.l1.Cost()
+}
+
+// Clear removes all entries from both tiers.
+func (c *Tiered[K, T]) Clear() {
+	c.l1.Clear()
+	_ = c.l2.clear()
+}
+
+// Close closes the first tier and the log file of the second tier, and returns their errors joined together.
+// The entries of the second tier are kept in the log file, so a new tiered cache can start with them.
+// The cache should not be used after Close.
+func (c *Tiered[K, T]) Close() error {
+	return errors.Join(c.l1.Close(), c.l2.close())
+}
+
+// Compact rewrites the live entries of the second tier to a new log file, removing the garbage of the current one.
+func (c *Tiered[K, T]) Compact() error {
+	if err := c.l2.compact(); err != nil {
+		return fmt.Errorf("failed to compact tiered cache; cause by %w", err)
+	}
+	return nil
+}
+
+// OnEvict sets the eviction hook of the first tier.
+// Entries dropped to the second tier are still passed to the hook with [EvictCapacity] as reason.
+func (c *Tiered[K, T]) OnEvict(hook func(key K, value T, reason EvictReason)) {
+	c.l1.OnEvict(hook)
+}
+
+// Stats returns the counters of the first tier,
+// so getting an entry from the second tier counts as a miss and a put.
+func (c *Tiered[K, T]) Stats() Stats {
+	return c.l1.Stats()
+}
+
+// ResetStats sets the counters of the first tier back to zero.
+func (c *Tiered[K, T]) ResetStats() {
+	c.l1.ResetStats()
+}
+
+// All returns an iterator of the keys and values in both tiers, going through the first tier like its All method,
+// then through the second tier from the latest entry written to the disk to the earliest.
+// Iterating does not move entries between the tiers, and values of the second tier are read from the disk as they are yielded.
+// Entries of the second tier that are removed or fail to be read during the iteration are skipped.
+func (c *Tiered[K, T]) All() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		for key, val := range c.l1.All() {
+			if !yield(key, val) {
+				return
+			}
+		}
+		for _, key := range c.l2.keys() {
+			if val, ok, err := c.l2.get(key); err == nil && ok {
+				if !yield(key, val) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Backward is like All, but goes through the second tier from the earliest entry written to the disk to the latest,
+// then through the first tier like its Backward method.
+func (c *Tiered[K, T]) Backward() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		keys := c.l2.keys()
+		for i := len(keys) - 1; i >= 0; i-- {
+			if val, ok, err := c.l2.get(keys[i]); err == nil && ok {
+				if !yield(keys[i], val) {
+					return
+				}
+			}
+		}
+		for key, val := range c.l1.Backward() {
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator of the keys in both tiers, in the same order as All.
+// Keys of the second tier are not read from the disk.
+func (c *Tiered[K, T]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range c.l1.Keys() {
+			if !yield(key) {
+				return
+			}
+		}
+		for _, key := range c.l2.keys() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator of the values in both tiers, in the same order as All.
+func (c *Tiered[K, T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, val := range c.All() {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
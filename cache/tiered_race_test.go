@@ -0,0 +1,63 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/trviph/collection/cache"
+)
+
+func TestTieredRace(t *testing.T) {
+	var wg sync.WaitGroup
+	tiered := cache.MustNewTiered(cache.MustNewLRU[int, int](randint(10, 50)), t.TempDir())
+	defer tiered.Close()
+
+	functions := []func(){
+		// Put to the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				tiered.Put(randint(0, 100), i)
+			}
+		},
+
+		// Get from the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = tiered.Get(randint(0, 100))
+			}
+		},
+
+		// Delete from the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = tiered.Delete(randint(0, 100))
+			}
+		},
+
+		// Iterate over the cache
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 100); i++ {
+				for range tiered.All() {
+				}
+			}
+		},
+
+		// Compact the log
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 100); i++ {
+				_ = tiered.Compact()
+			}
+		},
+	}
+
+	wg.Add(len(functions))
+	for _, f := range functions {
+		go f()
+	}
+	wg.Wait()
+}
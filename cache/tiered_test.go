@@ -0,0 +1,362 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/cache"
+)
+
+// A cache that is not of the cache package.
+type testForeignCache struct {
+	cache.Cache[int, string]
+}
+
+func TestNewTiered(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := cache.NewTiered[int, string](nil, dir); err == nil {
+		t.Errorf(testFailedMsg, "TestNewTiered", "error", err)
+	}
+	if _, err := cache.NewTiered(cache.MustNewLRU[int, string](2), ""); err == nil {
+		t.Errorf(testFailedMsg, "TestNewTiered", "error", err)
+	}
+	if _, err := cache.NewTiered[int, string](testForeignCache{cache.MustNewLRU[int, string](2)}, dir); err == nil {
+		t.Errorf(testFailedMsg, "TestNewTiered", "error", err)
+	}
+	foreignShards := cache.MustNewSharded(2, nil, func() cache.Cache[int, string] {
+		return testForeignCache{cache.MustNewLRU[int, string](2)}
+	})
+	if _, err := cache.NewTiered(foreignShards, dir); err == nil {
+		t.Errorf(testFailedMsg, "TestNewTiered", "error", err)
+	}
+	if _, err := cache.NewTiered(cache.MustNewLRU[int, string](2), dir, cache.WithCodec(nil)); err == nil {
+		t.Errorf(testFailedMsg, "TestNewTiered", "error", err)
+	}
+
+	// A file that is not a log
+	if err := os.WriteFile(filepath.Join(dir, "tiered.log"), []byte("NOTALOGFILE"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.NewTiered(cache.MustNewLRU[int, string](2), dir); err == nil {
+		t.Errorf(testFailedMsg, "TestNewTiered", "error", err)
+	}
+}
+
+func TestTiered(t *testing.T) {
+	lru := cache.MustNewLRU[int, string](2)
+	tiered := cache.MustNewTiered(lru, t.TempDir())
+	defer tiered.Close()
+
+	if _, err := tiered.Get(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestTiered", collection.ErrIsEmpty, err)
+	}
+
+	// 1 and 2 are dropped to the disk
+	tiered.Put(1, "A")
+	tiered.Put(2, "B")
+	tiered.Put(3, "C")
+	tiered.Put(4, "D")
+	if tiered.Len() != 4 || lru.Len() != 2 {
+		t.Errorf(testFailedMsg, "TestTiered", "4 entries with 2 in memory", tiered.Len())
+	}
+	if tiered.Cap() != 2 {
+		t.Errorf(testFailedMsg, "TestTiered", 2, tiered.Cap())
+	}
+	if !tiered.Contains(1) || lru.Contains(1) {
+		t.Errorf(testFailedMsg, "TestTiered", "1 on the disk", lru.Contains(1))
+	}
+
+	// Peek does not move the entry
+	if val, err := tiered.Peek(1); err != nil || val != "A" {
+		t.Errorf(testFailedMsg, "TestTiered", "A", val)
+	}
+	if lru.Contains(1) {
+		t.Errorf(testFailedMsg, "TestTiered", false, true)
+	}
+
+	// Get moves 1 into the memory, and 3 down to the disk
+	if val, err := tiered.Get(1); err != nil || val != "A" {
+		t.Errorf(testFailedMsg, "TestTiered", "A", val)
+	}
+	if !lru.Contains(1) || lru.Contains(3) || !tiered.Contains(3) {
+		t.Errorf(testFailedMsg, "TestTiered", "1 in memory and 3 on the disk", slices.Collect(lru.Keys()))
+	}
+	if tiered.Len() != 4 {
+		t.Errorf(testFailedMsg, "TestTiered", 4, tiered.Len())
+	}
+
+	// Putting a key on the disk replaces it
+	tiered.Put(3, "CC")
+	if val, err := tiered.Get(3); err != nil || val != "CC" {
+		t.Errorf(testFailedMsg, "TestTiered", "CC", val)
+	}
+
+	if !tiered.Delete(2) || tiered.Contains(2) {
+		t.Errorf(testFailedMsg, "TestTiered", false, tiered.Contains(2))
+	}
+	if tiered.Delete(2) {
+		t.Errorf(testFailedMsg, "TestTiered", false, true)
+	}
+	if _, err := tiered.Get(2); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestTiered", collection.ErrNotFound, err)
+	}
+
+	tiered.Clear()
+	if tiered.Len() != 0 {
+		t.Errorf(testFailedMsg, "TestTiered", 0, tiered.Len())
+	}
+	if _, err := tiered.Peek(4); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestTiered", collection.ErrIsEmpty, err)
+	}
+}
+
+func TestTieredOnEvict(t *testing.T) {
+	tiered := cache.MustNewTiered(cache.MustNewLRU[int, string](1), t.TempDir())
+	defer tiered.Close()
+
+	reasons := make([]cache.EvictReason, 0)
+	tiered.OnEvict(func(key int, value string, reason cache.EvictReason) {
+		reasons = append(reasons, reason)
+	})
+	tiered.Put(1, "A")
+	tiered.Put(2, "B")
+	tiered.Delete(2)
+
+	// The hook still sees the entry dropped to the disk
+	if want := []cache.EvictReason{cache.EvictCapacity, cache.EvictDeleted}; !slices.Equal(want, reasons) {
+		t.Errorf(testFailedMsg, "TestTieredOnEvict", want, reasons)
+	}
+	if !tiered.Contains(1) {
+		t.Errorf(testFailedMsg, "TestTieredOnEvict", true, false)
+	}
+}
+
+func TestTieredTTL(t *testing.T) {
+	clock := newFakeClock()
+	lru := cache.MustNewLRU[int, string](1, cache.WithClock(clock.Now))
+	tiered := cache.MustNewTiered(lru, t.TempDir(), cache.WithClock(clock.Now))
+	defer tiered.Close()
+
+	tiered.PutWithTTL(1, "A", time.Minute)
+	tiered.PutWithTTL(2, "B", time.Minute)
+	tiered.Put(3, "C")
+	clock.Advance(30 * time.Second)
+
+	// 1 keeps the time it had left when moved into the memory
+	if val, err := tiered.Get(1); err != nil || val != "A" {
+		t.Errorf(testFailedMsg, "TestTieredTTL", "A", val)
+	}
+	clock.Advance(30 * time.Second)
+	if tiered.Contains(1) || tiered.Contains(2) {
+		t.Errorf(testFailedMsg, "TestTieredTTL", "1 and 2 expired", slices.Collect(tiered.Keys()))
+	}
+	if tiered.Len() != 1 {
+		t.Errorf(testFailedMsg, "TestTieredTTL", 1, tiered.Len())
+	}
+	if _, err := tiered.Get(2); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestTieredTTL", collection.ErrNotFound, err)
+	}
+}
+
+func TestTieredGetOrLoad(t *testing.T) {
+	tiered := cache.MustNewTiered(cache.MustNewLRU[int, string](1), t.TempDir())
+	defer tiered.Close()
+
+	tiered.Put(1, "A")
+	tiered.Put(2, "B")
+	loaded := 0
+	loader := func(ctx context.Context, key int) (string, error) {
+		loaded++
+		return "loaded", nil
+	}
+
+	// 1 is on the disk, so it is not loaded
+	if val, err := tiered.GetOrLoad(context.Background(), 1, loader); err != nil || val != "A" {
+		t.Errorf(testFailedMsg, "TestTieredGetOrLoad", "A", val)
+	}
+	if val, err := tiered.GetOrLoad(context.Background(), 3, loader); err != nil || val != "loaded" {
+		t.Errorf(testFailedMsg, "TestTieredGetOrLoad", "loaded", val)
+	}
+	if loaded != 1 {
+		t.Errorf(testFailedMsg, "TestTieredGetOrLoad", 1, loaded)
+	}
+}
+
+func TestTieredAll(t *testing.T) {
+	tiered := cache.MustNewTiered(cache.MustNewLRU[int, string](2), t.TempDir())
+	defer tiered.Close()
+
+	for key := range 5 {
+		tiered.Put(key, string(rune('A'+key)))
+	}
+
+	// The memory from the most recently used, then the disk from the latest written
+	if want, got := []int{4, 3, 2, 1, 0}, slices.Collect(tiered.Keys()); !slices.Equal(want, got) {
+		t.Errorf(testFailedMsg, "TestTieredAll", want, got)
+	}
+	if want, got := []string{"E", "D", "C", "B", "A"}, slices.Collect(tiered.Values()); !slices.Equal(want, got) {
+		t.Errorf(testFailedMsg, "TestTieredAll", want, got)
+	}
+	got := make([]int, 0)
+	for key := range tiered.Backward() {
+		got = append(got, key)
+	}
+	if want := []int{0, 1, 2, 3, 4}; !slices.Equal(want, got) {
+		t.Errorf(testFailedMsg, "TestTieredAll", want, got)
+	}
+
+	// Iterating does not move entries
+	if tiered.Cost() != 2 {
+		t.Errorf(testFailedMsg, "TestTieredAll", 2, tiered.Cost())
+	}
+	count := 0
+	for range tiered.All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf(testFailedMsg, "TestTieredAll", 1, count)
+	}
+}
+
+func TestTieredReopen(t *testing.T) {
+	dir := t.TempDir()
+	tiered := cache.MustNewTiered(cache.MustNewLRU[int, string](1), dir, cache.WithCodec(testJSONCodec{}))
+	for key := range 5 {
+		tiered.Put(key, string(rune('A'+key)))
+	}
+	// Moved, replaced and deleted entries are not read back
+	_, _ = tiered.Get(0)
+	tiered.Put(1, "BB")
+	tiered.Delete(2)
+	tiered.Put(5, "F")
+	if err := tiered.Close(); err != nil {
+		t.Fatalf(testFailedMsg, "TestTieredReopen", "nil error", err)
+	}
+
+	// A record torn by a crash is dropped
+	f, err := os.OpenFile(filepath.Join(dir, "tiered.log"), os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.Write([]byte{0, 0, 1, 0, 1, 2, 3})
+	_ = f.Close()
+
+	reopened := cache.MustNewTiered(cache.MustNewLRU[int, string](1), dir, cache.WithCodec(testJSONCodec{}))
+	// Only 5 was in memory and is lost
+	if want, got := []int{1, 0, 4, 3}, slices.Collect(reopened.Keys()); !slices.Equal(want, got) {
+		t.Errorf(testFailedMsg, "TestTieredReopen", want, got)
+	}
+	if val, err := reopened.Get(1); err != nil || val != "BB" {
+		t.Errorf(testFailedMsg, "TestTieredReopen", "BB", val)
+	}
+
+	// The log can be written after the torn record is dropped
+	reopened.Put(6, "G")
+	if err := reopened.Close(); err != nil {
+		t.Fatalf(testFailedMsg, "TestTieredReopen", "nil error", err)
+	}
+	reopened = cache.MustNewTiered(cache.MustNewLRU[int, string](1), dir, cache.WithCodec(testJSONCodec{}))
+	defer reopened.Close()
+	if want, got := []int{1, 0, 4, 3}, slices.Collect(reopened.Keys()); !slices.Equal(want, got) {
+		t.Errorf(testFailedMsg, "TestTieredReopen", want, got)
+	}
+}
+
+func TestTieredCompact(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tiered.log")
+	tiered := cache.MustNewTiered(cache.MustNewLRU[int, string](1), dir)
+	for key := range 100 {
+		tiered.Put(key, "A")
+	}
+	for key := range 90 {
+		tiered.Delete(key)
+	}
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tiered.Compact(); err != nil {
+		t.Fatalf(testFailedMsg, "TestTieredCompact", "nil error", err)
+	}
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Size() >= before.Size() {
+		t.Errorf(testFailedMsg, "TestTieredCompact", "a smaller log", after.Size())
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf(testFailedMsg, "TestTieredCompact", "no temporary file", err)
+	}
+
+	// The entries keep their order, and are still there after reopening
+	want := []int{99, 98, 97, 96, 95, 94, 93, 92, 91, 90}
+	if got := slices.Collect(tiered.Keys()); !slices.Equal(want, got) {
+		t.Errorf(testFailedMsg, "TestTieredCompact", want, got)
+	}
+	if val, err := tiered.Peek(95); err != nil || val != "A" {
+		t.Errorf(testFailedMsg, "TestTieredCompact", "A", val)
+	}
+	tiered.Put(100, "B")
+	if err := tiered.Close(); err != nil {
+		t.Fatalf(testFailedMsg, "TestTieredCompact", "nil error", err)
+	}
+	reopened := cache.MustNewTiered(cache.MustNewLRU[int, string](1), dir)
+	defer reopened.Close()
+	if reopened.Len() != 10 {
+		t.Errorf(testFailedMsg, "TestTieredCompact", 10, reopened.Len())
+	}
+}
+
+func TestTieredAutoCompact(t *testing.T) {
+	dir := t.TempDir()
+	tiered := cache.MustNewTiered(cache.MustNewLRU[int, []byte](1), dir)
+	defer tiered.Close()
+
+	// Keep replacing the same entry on the disk
+	value := make([]byte, 64<<10)
+	for range 100 {
+		tiered.Put(0, value)
+		tiered.Put(1, value)
+	}
+	info, err := os.Stat(filepath.Join(dir, "tiered.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > 4<<20 {
+		t.Errorf(testFailedMsg, "TestTieredAutoCompact", "a log of at most 4 MiB", info.Size())
+	}
+	if tiered.Len() != 2 {
+		t.Errorf(testFailedMsg, "TestTieredAutoCompact", 2, tiered.Len())
+	}
+}
+
+func TestTieredSharded(t *testing.T) {
+	sharded := cache.MustNewSharded(2, testShardHash, func() cache.Cache[int, string] {
+		return cache.MustNewLRU[int, string](1)
+	})
+	tiered := cache.MustNewTiered(sharded, t.TempDir())
+	defer tiered.Close()
+
+	for key := range 6 {
+		tiered.Put(key, "A")
+	}
+	if tiered.Len() != 6 || sharded.Len() != 2 {
+		t.Errorf(testFailedMsg, "TestTieredSharded", "6 entries with 2 in memory", tiered.Len())
+	}
+	if val, err := tiered.Get(0); err != nil || val != "A" {
+		t.Errorf(testFailedMsg, "TestTieredSharded", "A", val)
+	}
+	if !sharded.Contains(0) || sharded.Contains(4) {
+		t.Errorf(testFailedMsg, "TestTieredSharded", "0 in memory and 4 on the disk", slices.Collect(sharded.Keys()))
+	}
+}
@@ -0,0 +1,227 @@
+package cache
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/internal"
+)
+
+// A cache using the W-TinyLFU eviction policy, which keeps entries that are used often over a long period,
+// while still being able to keep new entries that are used in bursts.
+// New entries are put in a small LRU window, which holds about 1% of the cache.
+// When the window is full, its least recently used entry competes with the entry that would be dropped
+// from the main SLRU area, and only the one estimated to be used more often is kept.
+//
+// How often keys are used is estimated by a [collection.CountMinSketch],
+// whose counts are halved after every ten times the capacity of the cache uses,
+// so keys that stop being used are forgotten over time.
+// Keys used only once are kept in a [collection.BloomFilter] doorkeeper instead of the sketch,
+// so they do not pollute the sketch.
+//
+// [TinyLFU: A Highly Efficient Cache Admission Policy]: https://arxiv.org/abs/1512.00727
+type TinyLFU[K comparable, T any] struct {
+	core[K, T]
+
+	// New entries, from most recently used to least recently used.
+	window lruList[K, T]
+	// Entries that have been admitted from the window.
+	main slruList[K, T]
+
+	// The maximum total cost of the window.
+	windowCap int64
+
+	hasher     keyHasher[K]
+	sketch     *collection.CountMinSketch
+	doorkeeper *collection.BloomFilter
+	// The number of uses recorded since the sketch was last halved, and the number of uses to halve it at.
+	samples, sampleSize int
+}
+
+var _ internal.Cache[int, any] = (*TinyLFU[int, any])(nil)
+
+// The maximum number of counters of each row of the sketch.
+const maxTinyLFUCounters = 1 << 20
+
+// [NewTinyLFU] creates a new cache with [TinyLFU] eviction policy.
+// It accepts cap as the first argument, specifying the maximum capacity of the cache,
+// followed by any [Option] to configure the cache.
+// It will return an error if cap is less than 1 or an option is invalid.
+func NewTinyLFU[K comparable, T any](cap int, opts ...Option) (*TinyLFU[K, T], error) {
+	if cap < 1 {
+		return nil, fmt.Errorf("failed to create tinylfu; cause by invalid specified capacity")
+	}
+	return newTinyLFU[K, T](int64(cap), nil, opts)
+}
+
+// Like [NewTinyLFU] but will panic on error.
+func MustNewTinyLFU[K comparable, T any](cap int, opts ...Option) *TinyLFU[K, T] {
+	return collection.Must(
+		func() (*TinyLFU[K, T], error) {
+			return NewTinyLFU[K, T](cap, opts...)
+		},
+	)
+}
+
+// [NewTinyLFUWithCost] is like [NewTinyLFU], but the capacity of the cache is measured by the cost of entries,
+// instead of the number of entries. It accepts maxCost as the first argument, specifying the maximum total cost of the cache,
+// and cost computing the cost of an entry, which should not be negative.
+// It will return an error if maxCost is less than 1, cost is nil or an option is invalid.
+func NewTinyLFUWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) (*TinyLFU[K, T], error) {
+	if err := checkCost(maxCost, cost); err != nil {
+		return nil, fmt.Errorf("failed to create tinylfu; cause by %w", err)
+	}
+	return newTinyLFU(maxCost, cost, opts)
+}
+
+// Like [NewTinyLFUWithCost] but will panic on error.
+func MustNewTinyLFUWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) *TinyLFU[K, T] {
+	return collection.Must(
+		func() (*TinyLFU[K, T], error) {
+			return NewTinyLFUWithCost(maxCost, cost, opts...)
+		},
+	)
+}
+
+func newTinyLFU[K comparable, T any](cap int64, cost func(key K, value T) int64, opts []Option) (*TinyLFU[K, T], error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tinylfu; cause by %w", err)
+	}
+
+	windowCap := max(cap/100, 1)
+	mainCap := cap - windowCap
+	// The number of keys to keep track of, which may be much less than cap if it is measured by cost
+	counters := int(min(cap, maxTinyLFUCounters))
+	c := &TinyLFU[K, T]{
+		window:     newLRUList[K, T](),
+		main:       newSLRUList[K, T](mainCap * 4 / 5),
+		windowCap:  windowCap,
+		hasher:     newKeyHasher[K](),
+		sketch:     collection.MustNewCountMinSketch(max(counters, 64), 4),
+		doorkeeper: collection.MustNewBloomFilter(counters, 0.01),
+		sampleSize: 10 * counters,
+	}
+	c.init(cap, cost, c, o)
+	return c, nil
+}
+
+func (c *TinyLFU[K, T]) lookup(key K) (*entry[K, T], bool) {
+	if e, ok := c.window.lookup(key); ok {
+		return e, true
+	}
+	return c.main.lookup(key)
+}
+
+func (c *TinyLFU[K, T]) touch(e *entry[K, T]) {
+	c.increment(e.key)
+	if _, ok := c.window.lookup(e.key); ok {
+		c.window.moveToFront(e)
+		return
+	}
+	c.main.touch(e)
+}
+
+func (c *TinyLFU[K, T]) miss(key K) {}
+
+func (c *TinyLFU[K, T]) add(e *entry[K, T]) {
+	c.increment(e.key)
+	c.window.pushFront(e)
+
+	// While the cache is not full, entries overflowing the window are admitted without competing.
+	for c.window.cost() > c.windowCap {
+		c.main.add(c.window.popBack())
+	}
+}
+
+func (c *TinyLFU[K, T]) remove(e *entry[K, T]) {
+	if _, ok := c.window.lookup(e.key); ok {
+		c.window.remove(e)
+		return
+	}
+	c.main.remove(e)
+}
+
+// Drop the less frequently used entry between the least recently used entry of the window,
+// and the entry that the main area would drop. The window entry is admitted to the main area if it is kept.
+// If the window is not full, the main area drops its entry without competing.
+func (c *TinyLFU[K, T]) evict() *entry[K, T] {
+	victim := c.main.back()
+	if c.window.cost() < c.windowCap && victim != nil {
+		return c.main.popBack()
+	}
+
+	candidate := c.window.back()
+	if candidate == nil {
+		// This should never happend
+		panic(
+			fmt.Errorf("something went very wrong; cannot drop TinyLFU entry of cache with capacity of %d, entries length %d", c.cap, c.len()),
+		)
+	}
+	if victim != nil && c.frequency(candidate.key) > c.frequency(victim.key) {
+		// Drop the victim before admitting the candidate, as the victim may be from the protected segment
+		// while the candidate goes to the probationary segment, which main would drop from first
+		c.main.popBack()
+		c.window.remove(candidate)
+		c.main.add(candidate)
+		return victim
+	}
+	return c.window.popBack()
+}
+
+func (c *TinyLFU[K, T]) len() int {
+	return c.window.len() + c.main.len()
+}
+
+func (c *TinyLFU[K, T]) clear() {
+	c.window.clear()
+	c.main.clear()
+	c.sketch.Reset()
+	c.doorkeeper.Reset()
+	c.samples = 0
+}
+
+// Iterate over entries of the window then the main area, each from the most recently used to the least.
+func (c *TinyLFU[K, T]) entries() iter.Seq[*entry[K, T]] {
+	return func(yield func(*entry[K, T]) bool) {
+		for e := range c.window.entries() {
+			if !yield(e) {
+				return
+			}
+		}
+		for e := range c.main.entries() {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// Record a use of the key. The first use only goes to the doorkeeper, later uses go to the sketch.
+func (c *TinyLFU[K, T]) increment(key K) {
+	hash := c.hasher.hash(key)
+	if c.doorkeeper.Contains(hash) {
+		c.sketch.Add(hash)
+	} else {
+		c.doorkeeper.Add(hash)
+	}
+
+	// Age the uses, so the keys that are no longer used can be dropped
+	c.samples++
+	if c.samples >= c.sampleSize {
+		c.sketch.Halve()
+		c.doorkeeper.Reset()
+		c.samples = 0
+	}
+}
+
+// Estimate how many times the key has been used recently.
+func (c *TinyLFU[K, T]) frequency(key K) int {
+	hash := c.hasher.hash(key)
+	estimate := c.sketch.Estimate(hash)
+	if c.doorkeeper.Contains(hash) {
+		estimate++
+	}
+	return estimate
+}
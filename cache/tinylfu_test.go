@@ -0,0 +1,147 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/cache"
+)
+
+func TestNewTinyLFU(t *testing.T) {
+	_, err := cache.NewTinyLFU[int, int](0)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewTinyLFU", "error", err)
+	}
+	_, err = cache.NewTinyLFU[int, int](1)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestNewTinyLFU", "nil error", err)
+	}
+}
+
+func TestMustNewTinyLFU(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewTinyLFU", "panic", r)
+		}
+	}()
+	_ = cache.MustNewTinyLFU[int, any](-1)
+}
+
+func TestTinyLFU(t *testing.T) {
+	// Create a cache the only hold 2 values at maximum, 1 in the window and 1 in the main area
+	tinylfu, err := cache.NewTinyLFU[int, string](2)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestTinyLFU", "nil error", err)
+	}
+
+	// Should get is empty error
+	if _, err := tinylfu.Get(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestTinyLFU", collection.ErrIsEmpty, err)
+	}
+
+	// A is used 4 times, and is moved to the main area by B
+	tinylfu.Put(1, "A")
+	for range 3 {
+		_, _ = tinylfu.Get(1)
+	}
+	tinylfu.Put(2, "B")
+
+	// B is used less than A, so it is not admitted
+	tinylfu.Put(3, "C")
+	if tinylfu.Contains(2) {
+		t.Errorf(testFailedMsg, "TestTinyLFU", false, true)
+	}
+	if !tinylfu.Contains(1) {
+		t.Errorf(testFailedMsg, "TestTinyLFU", true, false)
+	}
+
+	// C is used 6 times, more than A, so it is admitted in place of A
+	for range 5 {
+		_, _ = tinylfu.Get(3)
+	}
+	tinylfu.Put(4, "D")
+	if tinylfu.Contains(1) {
+		t.Errorf(testFailedMsg, "TestTinyLFU", false, true)
+	}
+	for _, key := range []int{3, 4} {
+		if !tinylfu.Contains(key) {
+			t.Errorf(testFailedMsg, "TestTinyLFU", true, false)
+		}
+	}
+}
+
+func TestTinyLFUEmptyProbation(t *testing.T) {
+	// The window holds a cost of 1, and the protected segment a cost of 7
+	tinylfu := cache.MustNewTinyLFUWithCost(10, func(key int, value string) int64 {
+		return int64(len(value))
+	})
+
+	// A overflows the window right away, then is promoted to the protected segment,
+	// so the probationary segment is empty
+	tinylfu.Put(1, "AAAAA")
+	_, _ = tinylfu.Get(1)
+	// B stays in the window, and is used more than A
+	tinylfu.Put(2, "B")
+	for range 3 {
+		_, _ = tinylfu.Get(2)
+	}
+
+	// C needs room, B wins against A which is taken from the protected segment
+	tinylfu.Put(3, "CCCCC")
+	if tinylfu.Contains(1) {
+		t.Errorf(testFailedMsg, "TestTinyLFUEmptyProbation", false, true)
+	}
+	for _, key := range []int{2, 3} {
+		if !tinylfu.Contains(key) {
+			t.Errorf(testFailedMsg, "TestTinyLFUEmptyProbation", true, false)
+		}
+	}
+	if got := tinylfu.Cost(); got != 6 {
+		t.Errorf(testFailedMsg, "TestTinyLFUEmptyProbation", 6, got)
+	}
+}
+
+func TestTinyLFUScan(t *testing.T) {
+	tinylfu := cache.MustNewTinyLFU[int, int](100)
+	for key := range 100 {
+		tinylfu.Put(key, key)
+	}
+	for range 5 {
+		for key := range 50 {
+			_, _ = tinylfu.Get(key)
+		}
+	}
+
+	// A scan of new entries does not flush the frequently used entries
+	for key := 1000; key < 3000; key++ {
+		tinylfu.Put(key, key)
+	}
+	for key := range 50 {
+		if !tinylfu.Contains(key) {
+			t.Errorf(testFailedMsg, "TestTinyLFUScan", true, false)
+		}
+	}
+	if tinylfu.Len() != 100 {
+		t.Errorf(testFailedMsg, "TestTinyLFUScan", 100, tinylfu.Len())
+	}
+}
+
+func TestTinyLFUAging(t *testing.T) {
+	tinylfu := cache.MustNewTinyLFU[int, int](2)
+	tinylfu.Put(1, 1)
+	for range 10 {
+		_, _ = tinylfu.Get(1)
+	}
+
+	// Once 1 is no longer used, its uses are forgotten over time and a new hot key can replace it
+	for key := 2; key < 100; key++ {
+		tinylfu.Put(key, key)
+		for range 3 {
+			_, _ = tinylfu.Get(key)
+		}
+	}
+	if tinylfu.Contains(1) {
+		t.Errorf(testFailedMsg, "TestTinyLFUAging", false, true)
+	}
+}
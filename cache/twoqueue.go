@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/internal"
+)
+
+// A cache using the 2Q eviction policy, which is resistant to scans of entries used only once.
+// New entries are first kept in the a1in FIFO queue, which holds about a quarter of the cache.
+// Keys dropped from a1in are remembered in the a1out ghost queue,
+// and are only promoted to the am LRU list if they are put again while still remembered.
+//
+// [2Q: A Low Overhead High Performance Buffer Management Replacement Algorithm]: https://www.vldb.org/conf/1994/P439.PDF
+type TwoQueue[K comparable, T any] struct {
+	core[K, T]
+
+	// Entries seen once recently, in first-in-first-out order, going from head to tail.
+	a1in lruList[K, T]
+	// Keys recently dropped from a1in, their entries hold no value.
+	a1out lruList[K, T]
+	// Entries seen at least twice, from most recently used to least recently used.
+	am lruList[K, T]
+
+	// The target cost of a1in and the maximum cost of a1out.
+	kin, kout int64
+
+	// If the key about to be added was found in a1out, set by miss and used by add.
+	missInA1out bool
+}
+
+var _ internal.Cache[int, any] = (*TwoQueue[int, any])(nil)
+
+// [NewTwoQueue] creates a new cache with [TwoQueue] eviction policy.
+// It accepts cap as the first argument, specifying the maximum capacity of the cache,
+// followed by any [Option] to configure the cache.
+// The cache also remembers keys of evicted entries up to half of cap, without their values.
+// It will return an error if cap is less than 1 or an option is invalid.
+func NewTwoQueue[K comparable, T any](cap int, opts ...Option) (*TwoQueue[K, T], error) {
+	if cap < 1 {
+		return nil, fmt.Errorf("failed to create 2q; cause by invalid specified capacity")
+	}
+	return newTwoQueue[K, T](int64(cap), nil, opts)
+}
+
+// Like [NewTwoQueue] but will panic on error.
+func MustNewTwoQueue[K comparable, T any](cap int, opts ...Option) *TwoQueue[K, T] {
+	return collection.Must(
+		func() (*TwoQueue[K, T], error) {
+			return NewTwoQueue[K, T](cap, opts...)
+		},
+	)
+}
+
+// [NewTwoQueueWithCost] is like [NewTwoQueue], but the capacity of the cache is measured by the cost of entries,
+// instead of the number of entries. It accepts maxCost as the first argument, specifying the maximum total cost of the cache,
+// and cost computing the cost of an entry, which should not be negative.
+// It will return an error if maxCost is less than 1, cost is nil or an option is invalid.
+func NewTwoQueueWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) (*TwoQueue[K, T], error) {
+	if err := checkCost(maxCost, cost); err != nil {
+		return nil, fmt.Errorf("failed to create 2q; cause by %w", err)
+	}
+	return newTwoQueue(maxCost, cost, opts)
+}
+
+// Like [NewTwoQueueWithCost] but will panic on error.
+func MustNewTwoQueueWithCost[K comparable, T any](maxCost int64, cost func(key K, value T) int64, opts ...Option) *TwoQueue[K, T] {
+	return collection.Must(
+		func() (*TwoQueue[K, T], error) {
+			return NewTwoQueueWithCost(maxCost, cost, opts...)
+		},
+	)
+}
+
+func newTwoQueue[K comparable, T any](cap int64, cost func(key K, value T) int64, opts []Option) (*TwoQueue[K, T], error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create 2q; cause by %w", err)
+	}
+
+	c := &TwoQueue[K, T]{
+		a1in:  newLRUList[K, T](),
+		a1out: newLRUList[K, T](),
+		am:    newLRUList[K, T](),
+		kin:   cap / 4,
+		kout:  max(cap/2, 1),
+	}
+	c.init(cap, cost, c, o)
+	return c, nil
+}
+
+func (c *TwoQueue[K, T]) lookup(key K) (*entry[K, T], bool) {
+	if e, ok := c.am.lookup(key); ok {
+		return e, true
+	}
+	return c.a1in.lookup(key)
+}
+
+func (c *TwoQueue[K, T]) touch(e *entry[K, T]) {
+	// Entries of a1in keep their place in the queue,
+	// as being used again shortly after the first use is not a sign of a hot entry.
+	if _, ok := c.am.lookup(e.key); ok {
+		c.am.moveToFront(e)
+	}
+}
+
+func (c *TwoQueue[K, T]) miss(key K) {
+	_, c.missInA1out = c.a1out.lookup(key)
+}
+
+func (c *TwoQueue[K, T]) add(e *entry[K, T]) {
+	// A key remembered in a1out has been seen before, so it goes straight to am.
+	// The key may have already been forgotten while making room for the entry.
+	if c.missInA1out {
+		if ghost, ok := c.a1out.lookup(e.key); ok {
+			c.a1out.remove(ghost)
+		}
+		c.am.pushFront(e)
+	} else {
+		c.a1in.pushFront(e)
+	}
+	c.missInA1out = false
+}
+
+func (c *TwoQueue[K, T]) remove(e *entry[K, T]) {
+	if _, ok := c.am.lookup(e.key); ok {
+		c.am.remove(e)
+		return
+	}
+	c.a1in.remove(e)
+}
+
+// Drop the oldest entry of a1in if it is over its target cost, else the least recently used entry of am.
+func (c *TwoQueue[K, T]) evict() *entry[K, T] {
+	if c.a1in.cost() > c.kin || c.am.len() == 0 {
+		if e := c.a1in.popBack(); e != nil {
+			// Remember the key, and forget the oldest keys if there are too many
+			c.a1out.pushFront(&entry[K, T]{key: e.key, cost: e.cost})
+			for c.a1out.cost() > c.kout {
+				_ = c.a1out.popBack()
+			}
+			return e
+		}
+	}
+
+	e := c.am.popBack()
+	if e == nil {
+		// This should never happend
+		panic(
+			fmt.Errorf("something went very wrong; cannot drop 2Q entry of cache with capacity of %d, entries length %d", c.cap, c.len()),
+		)
+	}
+	return e
+}
+
+func (c *TwoQueue[K, T]) len() int {
+	return c.a1in.len() + c.am.len()
+}
+
+func (c *TwoQueue[K, T]) clear() {
+	c.a1in.clear()
+	c.a1out.clear()
+	c.am.clear()
+}
+
+// Iterate over entries of am then a1in, each from the most recently added or used to the least.
+func (c *TwoQueue[K, T]) entries() iter.Seq[*entry[K, T]] {
+	return func(yield func(*entry[K, T]) bool) {
+		for e := range c.am.entries() {
+			if !yield(e) {
+				return
+			}
+		}
+		for e := range c.a1in.entries() {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package cache_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/cache"
+)
+
+func TestNewTwoQueue(t *testing.T) {
+	_, err := cache.NewTwoQueue[int, int](0)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewTwoQueue", "error", err)
+	}
+	_, err = cache.NewTwoQueue[int, int](1)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestNewTwoQueue", "nil error", err)
+	}
+}
+
+func TestMustNewTwoQueue(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewTwoQueue", "panic", r)
+		}
+	}()
+	_ = cache.MustNewTwoQueue[int, any](-1)
+}
+
+func TestTwoQueue(t *testing.T) {
+	// Create a cache the only hold 4 values at maximum,
+	// a1in holds 1 value and a1out remembers 2 keys.
+	twoQueue, err := cache.NewTwoQueue[int, string](4)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestTwoQueue", "nil error", err)
+	}
+
+	// Should get is empty error
+	if _, err := twoQueue.Get(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestTwoQueue", collection.ErrIsEmpty, err)
+	}
+
+	// A is the first in, so it is the first out of a1in, then it is remembered in a1out
+	for key, value := range []string{"A", "B", "C", "D", "E"} {
+		twoQueue.Put(key+1, value)
+	}
+	if twoQueue.Contains(1) {
+		t.Errorf(testFailedMsg, "TestTwoQueue", false, true)
+	}
+
+	// A is put again while remembered, so it goes to am
+	twoQueue.Put(1, "A")
+
+	// A scan of new entries only flush a1in, A is kept in am
+	for key := 6; key <= 8; key++ {
+		twoQueue.Put(key, "scan")
+	}
+	if val, err := twoQueue.Get(1); err != nil {
+		t.Errorf(testFailedMsg, "TestTwoQueue", "nil error", err)
+	} else if val != "A" {
+		t.Errorf(testFailedMsg, "TestTwoQueue", "A", val)
+	}
+	for key := 2; key <= 5; key++ {
+		if twoQueue.Contains(key) {
+			t.Errorf(testFailedMsg, "TestTwoQueue", false, true)
+		}
+	}
+
+	// Using an entry of a1in does not change its place, so 6 is still the first out
+	_, _ = twoQueue.Get(6)
+	twoQueue.Put(9, "I")
+	if twoQueue.Contains(6) {
+		t.Errorf(testFailedMsg, "TestTwoQueue", false, true)
+	}
+	for _, key := range []int{1, 7, 8, 9} {
+		if !twoQueue.Contains(key) {
+			t.Errorf(testFailedMsg, "TestTwoQueue", true, false)
+		}
+	}
+}
+
+func TestTwoQueueEvictAm(t *testing.T) {
+	twoQueue := cache.MustNewTwoQueue[int, string](4)
+
+	// Move 1, 2 and 3 to am by putting them again while they are remembered in a1out
+	for key := 1; key <= 6; key++ {
+		twoQueue.Put(key, "first")
+	}
+	for key := 1; key <= 3; key++ {
+		twoQueue.Put(key, "second")
+	}
+	if twoQueue.Len() != 4 {
+		t.Errorf(testFailedMsg, "TestTwoQueueEvictAm", 4, twoQueue.Len())
+	}
+
+	// a1in is within its target size, so the least recently used entry of am is evicted
+	_, _ = twoQueue.Get(1)
+	twoQueue.Put(7, "G")
+	twoQueue.Put(8, "H")
+	if twoQueue.Contains(2) {
+		t.Errorf(testFailedMsg, "TestTwoQueueEvictAm", false, true)
+	}
+	if !twoQueue.Contains(1) {
+		t.Errorf(testFailedMsg, "TestTwoQueueEvictAm", true, false)
+	}
+}
+
+func TestTwoQueueGhostCost(t *testing.T) {
+	// a1out remembers keys up to a total cost of 20
+	twoQueue := cache.MustNewTwoQueueWithCost(40, func(key int, value string) int64 {
+		return int64(len(value))
+	})
+
+	// Keys are put again while remembered in a1out, but with a different cost than their ghosts
+	for i := range 1000 {
+		twoQueue.Put(i%30, strings.Repeat("A", 1+i/30%2*9))
+
+		length, cost := cache.TwoQueueGhosts(twoQueue)
+		if cost < 0 || cost > 20 {
+			t.Fatalf(testFailedMsg, "TestTwoQueueGhostCost", "cost within [0, 20]", cost)
+		}
+		if int64(length) > cost {
+			t.Fatalf(testFailedMsg, "TestTwoQueueGhostCost", "length of at most the cost", length)
+		}
+	}
+}
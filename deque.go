@@ -0,0 +1,224 @@
+package collection
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+
+	"github.com/trviph/collection/internal"
+)
+
+// The smallest buffer of a [Deque] that is not empty.
+const dequeMinCap = 8
+
+// A double-ended queue [Deque] implemented by using a growable circular buffer as the base,
+// so values can be pushed and popped at both ends, and accessed by index, in constant time.
+// The buffer doubles when it is full, and halves when it is a quarter full.
+// All operation on [Deque] is thread-safe, because it only allow one goroutine at a time to access it data.
+type Deque[T any] struct {
+	mu sync.RWMutex
+	// The buffer, its length is always zero or a power of two.
+	values []T
+	// The position of the front value in the buffer.
+	head   int
+	length int
+}
+
+// Interface guard
+var _ internal.Deque[any] = (*Deque[any])(nil)
+
+// [NewDeque] creates a new [Deque] of type T, with the given values from front to back.
+//
+//	emptyDeque := NewDeque[int]()
+//	initializedDeque := NewDeque(1, 2, 3, 4, 5)
+func NewDeque[T any](values ...T) *Deque[T] {
+	d := &Deque[T]{}
+	d.PushBack(values...)
+	return d
+}
+
+// Length returns the number of values current in the deque.
+func (d *Deque[T]) Length() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.length
+}
+
+// PushFront adds values at the front of the deque, starting from left to right,
+// so the last value ends up at the front.
+func (d *Deque[T]) PushFront(values ...T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, value := range values {
+		d.grow()
+		d.head = d.index(len(d.values) - 1)
+		d.values[d.head] = value
+		d.length++
+	}
+}
+
+// PushBack adds values at the back of the deque, starting from left to right,
+// so the last value ends up at the back.
+func (d *Deque[T]) PushBack(values ...T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, value := range values {
+		d.grow()
+		d.values[d.index(d.length)] = value
+		d.length++
+	}
+}
+
+// PopFront removes and returns the value at the front of the deque.
+// If the deque is empty then return [ErrIsEmpty] as an error.
+func (d *Deque[T]) PopFront() (T, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var zeroValue T
+	if d.length == 0 {
+		return zeroValue, fmt.Errorf("failed to pop front of deque, cause by %w", ErrIsEmpty)
+	}
+	value := d.values[d.head]
+	d.values[d.head] = zeroValue
+	d.head = d.index(1)
+	d.length--
+	d.shrink()
+	return value, nil
+}
+
+// PopBack removes and returns the value at the back of the deque.
+// If the deque is empty then return [ErrIsEmpty] as an error.
+func (d *Deque[T]) PopBack() (T, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var zeroValue T
+	if d.length == 0 {
+		return zeroValue, fmt.Errorf("failed to pop back of deque, cause by %w", ErrIsEmpty)
+	}
+	at := d.index(d.length - 1)
+	value := d.values[at]
+	d.values[at] = zeroValue
+	d.length--
+	d.shrink()
+	return value, nil
+}
+
+// Front get the value at the front but does not remove it from the deque.
+// If the deque is empty return [ErrIsEmpty] as an error.
+func (d *Deque[T]) Front() (T, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.length == 0 {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to peek at the front of the deque, cause by %w", ErrIsEmpty)
+	}
+	return d.values[d.head], nil
+}
+
+// Back get the value at the back but does not remove it from the deque.
+// If the deque is empty return [ErrIsEmpty] as an error.
+func (d *Deque[T]) Back() (T, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.length == 0 {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to peek at the back of the deque, cause by %w", ErrIsEmpty)
+	}
+	return d.values[d.index(d.length-1)], nil
+}
+
+// At get the value at the given index, counting from the front which is at index 0.
+// If the deque is empty return [ErrIsEmpty] as an error,
+// else if the index is out of range return [ErrIndexOutOfRange] as an error.
+func (d *Deque[T]) At(i int) (T, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var zeroValue T
+	if d.length == 0 {
+		return zeroValue, fmt.Errorf("failed to get value at index %d from deque, cause by %w", i, ErrIsEmpty)
+	}
+	if i < 0 || i >= d.length {
+		return zeroValue, fmt.Errorf("failed to get value at index %d from deque, cause by %w", i, ErrIndexOutOfRange)
+	}
+	return d.values[d.index(i)], nil
+}
+
+// All return an iterator of values in the deque going from front to back.
+// The iterator returns the index and value.
+//
+//	for idx, val := range deque.All() {
+//	   // code goes here
+//	}
+func (d *Deque[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+
+		for i := 0; i < d.length; i++ {
+			if !yield(i, d.values[d.index(i)]) {
+				break
+			}
+		}
+	}
+}
+
+// Backward return an iterator of values in the deque going from back to front.
+// The iterator returns the index and value.
+//
+//	for idx, val := range deque.Backward() {
+//	   // code goes here
+//	}
+func (d *Deque[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+
+		for i := d.length - 1; i >= 0; i-- {
+			if !yield(i, d.values[d.index(i)]) {
+				break
+			}
+		}
+	}
+}
+
+// Get the position in the buffer of the value at the given index from the front.
+func (d *Deque[T]) index(i int) int {
+	// The length of the buffer is a power of two
+	return (d.head + i) & (len(d.values) - 1)
+}
+
+// Double the buffer if it is full.
+func (d *Deque[T]) grow() {
+	if d.length < len(d.values) {
+		return
+	}
+	d.resize(max(len(d.values)*2, dequeMinCap))
+}
+
+// Halve the buffer if it is a quarter full.
+func (d *Deque[T]) shrink() {
+	if len(d.values) > dequeMinCap && d.length <= len(d.values)/4 {
+		d.resize(len(d.values) / 2)
+	}
+}
+
+// Move the values to a new buffer of the given size, with the front value at the start.
+func (d *Deque[T]) resize(size int) {
+	values := make([]T, size)
+	if d.head+d.length <= len(d.values) {
+		copy(values, d.values[d.head:d.head+d.length])
+	} else {
+		n := copy(values, d.values[d.head:])
+		copy(values[n:], d.values[:d.length-n])
+	}
+	d.values = values
+	d.head = 0
+}
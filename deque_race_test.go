@@ -0,0 +1,74 @@
+package collection_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/trviph/collection"
+)
+
+func TestDequeRace(t *testing.T) {
+	var wg sync.WaitGroup
+	deque := collection.NewDeque[int]()
+	functions := []func(){
+		// Push to the front of the deque
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				deque.PushFront(rand.Int())
+			}
+		},
+
+		// Push to the back of the deque
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				deque.PushBack(rand.Int())
+			}
+		},
+
+		// Pop from the front of the deque
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = deque.PopFront()
+			}
+		},
+
+		// Pop from the back of the deque
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = deque.PopBack()
+			}
+		},
+
+		// Peek on the deque
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = deque.Front()
+				_, _ = deque.Back()
+				_, _ = deque.At(randint(0, 10))
+			}
+		},
+
+		// Iterate over the deque
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 100); i++ {
+				for range deque.All() {
+				}
+				for range deque.Backward() {
+				}
+			}
+		},
+	}
+
+	wg.Add(len(functions))
+	for _, f := range functions {
+		go f()
+	}
+	wg.Wait()
+}
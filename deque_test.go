@@ -0,0 +1,168 @@
+package collection_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/trviph/collection"
+)
+
+func TestNewDeque(t *testing.T) {
+	// New empty deque
+	emptyDeque := collection.NewDeque[int]()
+	if emptyDeque.Length() != 0 {
+		t.Errorf(testFailedMsg, "TestNewDeque", 0, emptyDeque.Length())
+	}
+	// New deque with values
+	deque := collection.NewDeque(1, 2, 3)
+	if deque.Length() != 3 {
+		t.Errorf(testFailedMsg, "TestNewDeque", 3, deque.Length())
+	}
+	if value, err := deque.Front(); err != nil || value != 1 {
+		t.Errorf(testFailedMsg, "TestNewDeque", 1, value)
+	}
+}
+
+func TestDequePush(t *testing.T) {
+	deque := collection.NewDeque[int]()
+
+	// Should got 3 2 1 4 5 6
+	deque.PushFront(1, 2, 3)
+	deque.PushBack(4, 5, 6)
+	want := []int{3, 2, 1, 4, 5, 6}
+	got := make([]int, 0)
+	for _, value := range deque.All() {
+		got = append(got, value)
+	}
+	if !slices.Equal(want, got) {
+		t.Errorf(testFailedMsg, "TestDequePush", want, got)
+	}
+	if value, err := deque.Back(); err != nil || value != 6 {
+		t.Errorf(testFailedMsg, "TestDequePush", 6, value)
+	}
+}
+
+func TestDequePop(t *testing.T) {
+	deque := collection.NewDeque[int]()
+	if _, err := deque.PopFront(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestDequePop", collection.ErrIsEmpty, err)
+	}
+	if _, err := deque.PopBack(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestDequePop", collection.ErrIsEmpty, err)
+	}
+
+	deque.PushBack(1, 2, 3, 4)
+	if value, err := deque.PopFront(); err != nil || value != 1 {
+		t.Errorf(testFailedMsg, "TestDequePop", 1, value)
+	}
+	if value, err := deque.PopBack(); err != nil || value != 4 {
+		t.Errorf(testFailedMsg, "TestDequePop", 4, value)
+	}
+	if deque.Length() != 2 {
+		t.Errorf(testFailedMsg, "TestDequePop", 2, deque.Length())
+	}
+}
+
+func TestDequePeek(t *testing.T) {
+	deque := collection.NewDeque[int]()
+	if _, err := deque.Front(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestDequePeek", collection.ErrIsEmpty, err)
+	}
+	if _, err := deque.Back(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestDequePeek", collection.ErrIsEmpty, err)
+	}
+
+	deque.PushBack(1, 2)
+	if value, err := deque.Front(); err != nil || value != 1 {
+		t.Errorf(testFailedMsg, "TestDequePeek", 1, value)
+	}
+	if value, err := deque.Back(); err != nil || value != 2 {
+		t.Errorf(testFailedMsg, "TestDequePeek", 2, value)
+	}
+	// Should not remove the values
+	if deque.Length() != 2 {
+		t.Errorf(testFailedMsg, "TestDequePeek", 2, deque.Length())
+	}
+}
+
+func TestDequeAt(t *testing.T) {
+	deque := collection.NewDeque[int]()
+	if _, err := deque.At(0); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestDequeAt", collection.ErrIsEmpty, err)
+	}
+
+	// Should wrap around the end of the buffer
+	deque.PushBack(3, 4, 5, 6, 7)
+	deque.PushFront(2, 1, 0)
+	for i := range 8 {
+		if value, err := deque.At(i); err != nil || value != i {
+			t.Errorf(testFailedMsg, "TestDequeAt", i, value)
+		}
+	}
+	if _, err := deque.At(-1); !errors.Is(err, collection.ErrIndexOutOfRange) {
+		t.Errorf(testFailedMsg, "TestDequeAt", collection.ErrIndexOutOfRange, err)
+	}
+	if _, err := deque.At(8); !errors.Is(err, collection.ErrIndexOutOfRange) {
+		t.Errorf(testFailedMsg, "TestDequeAt", collection.ErrIndexOutOfRange, err)
+	}
+}
+
+func TestDequeGrowShrink(t *testing.T) {
+	deque := collection.NewDeque[int]()
+
+	// Should keep the order while the buffer grows and shrinks
+	for i := range 1000 {
+		if i%2 == 0 {
+			deque.PushBack(i)
+		} else {
+			deque.PushFront(i)
+		}
+	}
+	for i := 999; i >= 0; i-- {
+		var value int
+		var err error
+		if i%2 == 0 {
+			value, err = deque.PopBack()
+		} else {
+			value, err = deque.PopFront()
+		}
+		if err != nil || value != i {
+			t.Fatalf(testFailedMsg, "TestDequeGrowShrink", i, value)
+		}
+	}
+	if deque.Length() != 0 {
+		t.Errorf(testFailedMsg, "TestDequeGrowShrink", 0, deque.Length())
+	}
+}
+
+func TestDequeBackward(t *testing.T) {
+	deque := collection.NewDeque(1, 2, 3)
+	deque.PushFront(0)
+
+	wantIdx := []int{3, 2, 1, 0}
+	wantVal := []int{3, 2, 1, 0}
+	gotIdx := make([]int, 0)
+	gotVal := make([]int, 0)
+	for idx, val := range deque.Backward() {
+		gotIdx = append(gotIdx, idx)
+		gotVal = append(gotVal, val)
+	}
+	if !slices.Equal(wantIdx, gotIdx) || !slices.Equal(wantVal, gotVal) {
+		t.Errorf(testFailedMsg, "TestDequeBackward", wantVal, gotVal)
+	}
+
+	// Should stop when breaking out of the loop
+	count := 0
+	for range deque.Backward() {
+		count++
+		break
+	}
+	for range deque.All() {
+		count++
+		break
+	}
+	if count != 2 {
+		t.Errorf(testFailedMsg, "TestDequeBackward", 2, count)
+	}
+}
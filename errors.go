@@ -6,4 +6,6 @@ var (
 	ErrIsEmpty         error = fmt.Errorf("is empty")
 	ErrNotFound        error = fmt.Errorf("not found")
 	ErrIndexOutOfRange error = fmt.Errorf("index is out of range")
+	ErrClosed          error = fmt.Errorf("is closed")
+	ErrFull            error = fmt.Errorf("is full")
 )
@@ -0,0 +1,367 @@
+package collection
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/trviph/collection/internal"
+)
+
+// A [FibonacciHeap] implemented by using a circular list of trees, whose nodes are kept in circular lists of siblings.
+// Push, Meld and Top take O(1), decreasing a value takes O(1) amortized, and Pop takes O(log n) amortized,
+// as the trees are only consolidated when popping.
+// FibonacciHeap is thread-safe, because it only allow one goroutine at a time to access it data.
+//
+// See [NewHeap] for how the values are compared.
+type FibonacciHeap[T any] struct {
+	mu sync.RWMutex
+	id uint64
+	// The root that satisfies cmp against all other roots, it is also the entry of the list of roots.
+	top  *FibonacciHeapItem[T]
+	size int
+	cmp  func(T, T) bool
+	// The owner of the nodes pushed into this heap.
+	owner *heapOwner
+	// Reused by Pop to consolidate the roots.
+	roots   []*FibonacciHeapItem[T]
+	degrees []*FibonacciHeapItem[T]
+}
+
+// A node of a [FibonacciHeap], which is also the handle returned by [FibonacciHeap.PushItem],
+// and can be given to [FibonacciHeap.Update] and [FibonacciHeap.Remove].
+// The handle is no longer valid once its value leaves the heap.
+type FibonacciHeapItem[T any] struct {
+	value  T
+	parent *FibonacciHeapItem[T]
+	// Any of the children.
+	child *FibonacciHeapItem[T]
+	// The siblings in the circular list, a node without siblings points to itself.
+	left, right *FibonacciHeapItem[T]
+	// The number of children.
+	degree int
+	// Whether the node lost a child since it became the child of its parent.
+	mark bool
+	// The owner of the heap the node was pushed into, it never changes.
+	owner  *heapOwner
+	inHeap bool
+}
+
+var _ internal.Heap[any] = (*FibonacciHeap[any])(nil)
+
+// [NewFibonacciHeap] creates a new [FibonacciHeap], see [NewHeap] for cmp.
+// This will return an error if cmp is nil, if you want to panic instead use [MustNewFibonacciHeap].
+//
+//	heap, err := collection.NewFibonacciHeap(collection.LessThan[int])
+func NewFibonacciHeap[T any](cmp func(current, other T) bool) (*FibonacciHeap[T], error) {
+	if cmp == nil {
+		return nil, fmt.Errorf("function argument is required to create a new fibonacci heap")
+	}
+
+	return &FibonacciHeap[T]{id: heapIDs.Add(1), cmp: cmp, owner: &heapOwner{}}, nil
+}
+
+// Like [NewFibonacciHeap] but will panic if cmp is nil.
+func MustNewFibonacciHeap[T any](cmp func(current, other T) bool) *FibonacciHeap[T] {
+	return Must(func() (*FibonacciHeap[T], error) {
+		return NewFibonacciHeap(cmp)
+	})
+}
+
+// Push values into the heap.
+func (h *FibonacciHeap[T]) Push(values ...T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, value := range values {
+		h.push(value)
+	}
+}
+
+// PushItem pushes a value into the heap like [FibonacciHeap.Push],
+// and returns a handle to it, to update or remove the value later.
+func (h *FibonacciHeap[T]) PushItem(value T) *FibonacciHeapItem[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.push(value)
+}
+
+func (h *FibonacciHeap[T]) push(value T) *FibonacciHeapItem[T] {
+	node := &FibonacciHeapItem[T]{value: value, owner: h.owner, inHeap: true}
+	node.left, node.right = node, node
+	h.addRoot(node)
+	h.size++
+	return node
+}
+
+// Get a value at the root node, and remove it from the heap.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *FibonacciHeap[T]) Pop() (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.top == nil {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to pop on fibonacci heap, cause %w", ErrIsEmpty)
+	}
+	return h.pop(), nil
+}
+
+// Remove the top node, move its children to the roots, then consolidate the roots
+// so that no two of them have the same degree, and return the value of the removed node.
+func (h *FibonacciHeap[T]) pop() T {
+	node := h.top
+	roots := h.roots[:0]
+	for curr := node.right; curr != node; curr = curr.right {
+		roots = append(roots, curr)
+	}
+	if child := node.child; child != nil {
+		curr := child
+		for {
+			roots = append(roots, curr)
+			curr.parent = nil
+			curr.mark = false
+			if curr = curr.right; curr == child {
+				break
+			}
+		}
+	}
+	h.size--
+	h.detach(node)
+
+	// Link the roots of the same degree until every degree is unique
+	degrees := h.degrees[:0]
+	for _, root := range roots {
+		root.left, root.right = root, root
+		for root.degree < len(degrees) && degrees[root.degree] != nil {
+			other := degrees[root.degree]
+			degrees[root.degree] = nil
+			if !h.cmp(root.value, other.value) {
+				root, other = other, root
+			}
+			h.addChild(root, other)
+		}
+		for root.degree >= len(degrees) {
+			degrees = append(degrees, nil)
+		}
+		degrees[root.degree] = root
+	}
+
+	// Rebuild the list of roots, and find the new top
+	h.top = nil
+	for i, root := range degrees {
+		if root != nil {
+			h.addRoot(root)
+			degrees[i] = nil
+		}
+	}
+	clear(roots)
+	h.roots, h.degrees = roots, degrees
+	return node.value
+}
+
+// Push a value into the heap and then pop the root node.
+// This function is equivalent to call a [FibonacciHeap.Push] followed by a [FibonacciHeap.Pop],
+// but does not allocate a node if the value would be the root.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *FibonacciHeap[T]) PushPop(value T) (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.top == nil {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to push and pop on fibonacci heap, cause %w", ErrIsEmpty)
+	}
+	if h.cmp(value, h.top.value) {
+		return value, nil
+	}
+	res := h.pop()
+	h.push(value)
+	return res, nil
+}
+
+// Peek at the value at the root node without removing it from the heap.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *FibonacciHeap[T]) Top() (T, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.top == nil {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to peek at fibonacci heap, cause %w", ErrIsEmpty)
+	}
+	return h.top.value, nil
+}
+
+// IsEmpty returns true if the heap does not hold any value.
+func (h *FibonacciHeap[T]) IsEmpty() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.top == nil
+}
+
+// Len returns the number of values in the heap.
+func (h *FibonacciHeap[T]) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.size
+}
+
+// Update replaces the value of the handle, and moves it to its new place in the heap.
+// If the new value moves toward the root, this takes O(1) amortized, else O(log n) amortized.
+// Returns [ErrNotFound] if the value of the handle is no longer in the heap, or the handle belongs to another heap.
+func (h *FibonacciHeap[T]) Update(item *FibonacciHeapItem[T], value T) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.has(item) {
+		return fmt.Errorf("failed to update fibonacci heap, cause %w", ErrNotFound)
+	}
+
+	if h.cmp(value, item.value) {
+		// The children of the node are still in order, only its parent may not be
+		item.value = value
+		if parent := item.parent; parent != nil && !h.cmp(parent.value, item.value) {
+			h.cut(item)
+			h.cascadingCut(parent)
+		}
+		if h.cmp(item.value, h.top.value) {
+			h.top = item
+		}
+		return nil
+	}
+
+	// Else the node may have to go below its children, take it out and push it again
+	h.remove(item)
+	item.value = value
+	item.inHeap = true
+	item.left, item.right = item, item
+	h.addRoot(item)
+	h.size++
+	return nil
+}
+
+// Remove removes the value of the handle from the heap, and returns it.
+// Returns [ErrNotFound] if the value of the handle is no longer in the heap, or the handle belongs to another heap.
+func (h *FibonacciHeap[T]) Remove(item *FibonacciHeapItem[T]) (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.has(item) {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to remove from fibonacci heap, cause %w", ErrNotFound)
+	}
+	h.remove(item)
+	return item.value, nil
+}
+
+// Remove a node from anywhere in the heap, by moving it to the roots and popping it as if it was the top.
+func (h *FibonacciHeap[T]) remove(node *FibonacciHeapItem[T]) {
+	if parent := node.parent; parent != nil {
+		h.cut(node)
+		h.cascadingCut(parent)
+	}
+	h.top = node
+	h.pop()
+}
+
+// Meld moves all the values of other into the heap in O(1), leaving other empty.
+// The handles of the values of other now belong to the heap.
+// Both heaps should compare values the same way.
+func (h *FibonacciHeap[T]) Meld(other *FibonacciHeap[T]) {
+	if other == nil || other == h {
+		return
+	}
+	first, second := h, other
+	if first.id > second.id {
+		first, second = second, first
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	if other.top != nil {
+		h.addRoot(other.top)
+	}
+	h.size += other.size
+	h.owner = h.owner.union(other.owner)
+	other.owner = &heapOwner{}
+	other.top = nil
+	other.size = 0
+}
+
+// Check if the node of the handle is in this heap.
+func (h *FibonacciHeap[T]) has(item *FibonacciHeapItem[T]) bool {
+	return item != nil && item.owner.find() == h.owner && item.inHeap
+}
+
+// Splice a circular list of roots into the roots, and update the top.
+func (h *FibonacciHeap[T]) addRoot(node *FibonacciHeapItem[T]) {
+	if h.top == nil {
+		h.top = node
+		return
+	}
+	splice(h.top, node)
+	if h.cmp(node.value, h.top.value) {
+		h.top = node
+	}
+}
+
+// Make a root without siblings the child of another root.
+func (h *FibonacciHeap[T]) addChild(parent, node *FibonacciHeapItem[T]) {
+	node.parent = parent
+	node.mark = false
+	if parent.child == nil {
+		parent.child = node
+	} else {
+		splice(parent.child, node)
+	}
+	parent.degree++
+}
+
+// Cut a node from its parent and move it to the roots.
+func (h *FibonacciHeap[T]) cut(node *FibonacciHeapItem[T]) {
+	parent := node.parent
+	if node.right == node {
+		parent.child = nil
+	} else {
+		if parent.child == node {
+			parent.child = node.right
+		}
+		node.left.right, node.right.left = node.right, node.left
+		node.left, node.right = node, node
+	}
+	parent.degree--
+	node.parent = nil
+	node.mark = false
+	h.addRoot(node)
+}
+
+// Mark a node that lost a child, or cut it too if it already lost one, so the trees stay wide.
+func (h *FibonacciHeap[T]) cascadingCut(node *FibonacciHeapItem[T]) {
+	for parent := node.parent; parent != nil; node, parent = parent, parent.parent {
+		if !node.mark {
+			node.mark = true
+			return
+		}
+		h.cut(node)
+	}
+}
+
+// Unlink a node that left the heap, so it does not keep the other nodes alive.
+func (h *FibonacciHeap[T]) detach(node *FibonacciHeapItem[T]) {
+	node.parent, node.child, node.left, node.right = nil, nil, nil, nil
+	node.degree = 0
+	node.mark = false
+	node.inHeap = false
+}
+
+// Join two circular lists, after a and before the node to the right of a.
+func splice[T any](a, b *FibonacciHeapItem[T]) {
+	aRight, bLeft := a.right, b.left
+	a.right, b.left = b, a
+	bLeft.right, aRight.left = aRight, bLeft
+}
@@ -2,6 +2,8 @@ package collection
 
 import (
 	"fmt"
+	"iter"
+	"slices"
 	"sync"
 
 	"github.com/trviph/collection/internal"
@@ -10,11 +12,26 @@ import (
 // A [Heap] implemented using slice, a dynamic array implementation,  as the base.
 // Heap is thread-safe, because it only allow one goroutine at a time to access it data.
 //
+// By default every node has two children, see [NewDaryHeap] for a heap whose nodes have more children.
+//
 // [Go Slices: usage and internals]: https://go.dev/blog/slices-intro
 type Heap[T any] struct {
 	mu     sync.RWMutex
 	values []T
 	cmp    func(T, T) bool
+	// The arity, the maximum number of children of a node.
+	d int
+	// The handles of the values at the same index, nil for values pushed without a handle.
+	// It is only allocated once PushItem is called, so a heap without handles does not pay for them.
+	items []*HeapItem[T]
+}
+
+// A handle to a value pushed by [Heap.PushItem], which can be given to [Heap.Update] and [Heap.Remove].
+// The handle is no longer valid once its value leaves the heap.
+type HeapItem[T any] struct {
+	heap *Heap[T]
+	// The index of the value in the heap, -1 if the value is no longer in the heap.
+	index int
 }
 
 var _ internal.Heap[any] = (*Heap[any])(nil)
@@ -42,7 +59,7 @@ func NewHeap[T any](cmp func(current, other T) bool) (*Heap[T], error) {
 	}
 
 	return &Heap[T]{
-		values: make([]T, 0), cmp: cmp,
+		values: make([]T, 0), cmp: cmp, d: 2,
 	}, nil
 }
 
@@ -53,16 +70,138 @@ func MustNewHeap[T any](cmp func(current, other T) bool) *Heap[T] {
 	})
 }
 
+// [NewDaryHeap] creates a new [Heap] whose nodes have d children instead of two, see [NewHeap] for cmp.
+// A larger d makes the heap shallower, so Push and Update swim through fewer nodes,
+// and the children of a node are next to each other in memory, so Pop has fewer cache misses
+// though it compares more children at each level.
+// This will return an error if d is less than 2 or cmp is nil, if you want to panic instead use [MustNewDaryHeap].
+//
+//	heap, err := collection.NewDaryHeap(4, collection.LessThan[int])
+func NewDaryHeap[T any](d int, cmp func(current, other T) bool) (*Heap[T], error) {
+	if d < 2 {
+		return nil, fmt.Errorf("failed to create d-ary heap; cause by invalid specified arity of %d", d)
+	}
+	if h, err := NewHeap(cmp); err != nil {
+		return nil, err
+	} else {
+		h.d = d
+		return h, nil
+	}
+}
+
+// Like [NewDaryHeap] but will panic on error.
+func MustNewDaryHeap[T any](d int, cmp func(current, other T) bool) *Heap[T] {
+	return Must(func() (*Heap[T], error) {
+		return NewDaryHeap(d, cmp)
+	})
+}
+
+// [NewHeapFrom] creates a new [Heap] holding a copy of the given values, see [NewHeap] for cmp.
+// The heap is built bottom-up in O(n), instead of O(n log n) when pushing the values one by one.
+// This will return an error if cmp is nil, if you want to panic instead use [MustNewHeapFrom].
+//
+//	heap, err := collection.NewHeapFrom(collection.LessThan, []int{5, 3, 8, 1})
+func NewHeapFrom[T any](cmp func(current, other T) bool, values []T) (*Heap[T], error) {
+	if cmp == nil {
+		return nil, fmt.Errorf("function argument is required to create a new heap")
+	}
+
+	h := &Heap[T]{values: slices.Clone(values), cmp: cmp, d: 2}
+	if h.values == nil {
+		h.values = make([]T, 0)
+	}
+	h.heapify()
+	return h, nil
+}
+
+// Like [NewHeapFrom] but will panic if cmp is nil.
+func MustNewHeapFrom[T any](cmp func(current, other T) bool, values []T) *Heap[T] {
+	return Must(func() (*Heap[T], error) {
+		return NewHeapFrom(cmp, values)
+	})
+}
+
 // Push values into the Heap.
+// If there are more values than the heap already holds, the heap is rebuilt bottom-up in O(n)
+// instead of pushing the values one by one.
 func (h *Heap[T]) Push(values ...T) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	for _, value := range values {
-		h.values = append(h.values, value)
-		// Swim the node that just got inserted to it approriate place
-		h.swim()
+	h.pushAll(values)
+}
+
+// Push many values, rebuilding the heap if it is cheaper.
+func (h *Heap[T]) pushAll(values []T) {
+	if len(values) <= len(h.values) {
+		for _, value := range values {
+			h.push(value)
+		}
+		return
+	}
+
+	h.values = append(h.values, values...)
+	if h.items != nil {
+		h.items = append(h.items, make([]*HeapItem[T], len(values))...)
+	}
+	h.heapify()
+}
+
+func (h *Heap[T]) push(value T) {
+	h.values = append(h.values, value)
+	if h.items != nil {
+		h.items = append(h.items, nil)
+	}
+	// Swim the node that just got inserted to it approriate place
+	h.swim(len(h.values) - 1)
+}
+
+// PushItem pushes a value into the heap like [Heap.Push],
+// and returns a handle to it, to update or remove the value later in O(log n).
+func (h *Heap[T]) PushItem(value T) *HeapItem[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.items == nil {
+		h.items = make([]*HeapItem[T], len(h.values), cap(h.values))
+	}
+	item := &HeapItem[T]{heap: h, index: len(h.values)}
+	h.values = append(h.values, value)
+	h.items = append(h.items, item)
+	h.swim(item.index)
+	return item
+}
+
+// Update replaces the value of the handle, and moves it to its new place in the heap.
+// Returns [ErrNotFound] if the value of the handle is no longer in the heap, or the handle belongs to another heap.
+func (h *Heap[T]) Update(item *HeapItem[T], value T) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.has(item) {
+		return fmt.Errorf("failed to update heap, cause %w", ErrNotFound)
 	}
+	h.values[item.index] = value
+	h.fix(item.index)
+	return nil
+}
+
+// Remove removes the value of the handle from the heap, and returns it.
+// Returns [ErrNotFound] if the value of the handle is no longer in the heap, or the handle belongs to another heap.
+func (h *Heap[T]) Remove(item *HeapItem[T]) (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.has(item) {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to remove from heap, cause %w", ErrNotFound)
+	}
+	return h.removeAt(item.index), nil
+}
+
+// Check if the value of the handle is in this heap.
+func (h *Heap[T]) has(item *HeapItem[T]) bool {
+	return item != nil && item.heap == h && item.index >= 0
 }
 
 // Get a value at the root node, and remove it from the Heap.
@@ -76,17 +215,38 @@ func (h *Heap[T]) Pop() (T, error) {
 		return res, fmt.Errorf("failed to pop on heap, cause %w", ErrIsEmpty)
 	}
 
-	// Get value from the root
-	res = h.values[0]
+	return h.removeAt(0), nil
+}
+
+// Remove the value at the given index, and return it.
+func (h *Heap[T]) removeAt(idx int) T {
+	// Swap the final node to the index
+	last := len(h.values) - 1
+	h.swap(idx, last)
+	res := h.values[last]
 
-	// Swap the final node to the root
-	h.values[0] = h.values[len(h.values)-1]
 	// Shorten the underlying array
-	h.values = h.values[:len(h.values)-1]
-	// Sink the root node down to it apporiate place
-	h.sink()
+	var zeroValue T
+	h.values[last] = zeroValue
+	h.values = h.values[:last]
+	if h.items != nil {
+		h.detach(last)
+		h.items = h.items[:last]
+	}
 
-	return res, nil
+	// Move the swapped node to it apporiate place
+	if idx < last {
+		h.fix(idx)
+	}
+	return res
+}
+
+// Invalidate the handle of the value at the given index, if there is any.
+func (h *Heap[T]) detach(idx int) {
+	if item := h.items[idx]; item != nil {
+		item.index = -1
+		h.items[idx] = nil
+	}
 }
 
 // Push a value into the heap and then pop the root node.
@@ -113,12 +273,165 @@ func (h *Heap[T]) PushPop(value T) (T, error) {
 	} else {
 		res = h.values[0]
 		h.values[0] = value
-		h.sink()
+		if h.items != nil {
+			h.detach(0)
+		}
+		h.sink(0)
 	}
 
 	return res, nil
 }
 
+// Replace pops the root node and then pushes the value.
+// This function is equivalent to call a [Heap.Pop] followed by a [Heap.Push],
+// but have a more efficient implementation. Unlike [Heap.PushPop],
+// the returned value is always the previous root, even if the new value would be the root.
+// Returns [ErrIsEmpty] if the [Heap] is empty, the value is not pushed in that case.
+func (h *Heap[T]) Replace(value T) (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var res T
+	if h.isEmpty() {
+		return res, fmt.Errorf("failed to replace on heap, cause %w", ErrIsEmpty)
+	}
+
+	res = h.values[0]
+	h.values[0] = value
+	if h.items != nil {
+		h.detach(0)
+	}
+	h.sink(0)
+	return res, nil
+}
+
+// PopN pops at most k values from the heap, in the order they would be popped by [Heap.Pop].
+// It returns fewer values if the heap runs out, and none if k is not positive.
+func (h *Heap[T]) PopN(k int) []T {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	k = min(k, len(h.values))
+	if k <= 0 {
+		return []T{}
+	}
+	res := make([]T, k)
+	for i := range res {
+		res[i] = h.removeAt(0)
+	}
+	return res
+}
+
+// Merge pushes all the values of other into the heap, other is left unchanged.
+// Handles of the values of other still belong to other.
+func (h *Heap[T]) Merge(other *Heap[T]) {
+	// Copy the values first, so the two heaps are never locked at the same time
+	other.mu.RLock()
+	values := slices.Clone(other.values)
+	other.mu.RUnlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.pushAll(values)
+}
+
+// Len returns the number of values in the heap.
+func (h *Heap[T]) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.values)
+}
+
+// Clear removes all values from the heap, the handles of the values are no longer valid.
+func (h *Heap[T]) Clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, item := range h.items {
+		if item != nil {
+			item.index = -1
+		}
+	}
+	h.values = make([]T, 0)
+	h.items = nil
+}
+
+// All return an iterator of values in the heap, in the order they are stored in the underlying array,
+// which starts with the root but is otherwise not sorted.
+//
+//	for val := range heap.All() {
+//	   // code goes here
+//	}
+func (h *Heap[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+
+		for _, value := range h.values {
+			if !yield(value) {
+				break
+			}
+		}
+	}
+}
+
+// Sorted return an iterator of values in the heap, in the order they would be popped by [Heap.Pop],
+// but without removing them from the heap.
+// Getting the first k values takes O(k log k), as only the nodes whose parent is already yielded are compared.
+//
+//	for val := range heap.Sorted() {
+//	   // code goes here
+//	}
+func (h *Heap[T]) Sorted() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+
+		if h.isEmpty() {
+			return
+		}
+		// An auxiliary heap of the indices of the nodes that can be yielded next, starting with the root
+		aux := &Heap[int]{
+			values: []int{0},
+			d:      2,
+			cmp: func(current, other int) bool {
+				return h.cmp(h.values[current], h.values[other])
+			},
+		}
+		for !aux.isEmpty() {
+			idx := aux.removeAt(0)
+			if !yield(h.values[idx]) {
+				break
+			}
+			firstIDX, endIDX := h.getChildrenIDX(idx)
+			for childIDX := firstIDX; childIDX < endIDX; childIDX++ {
+				aux.push(childIDX)
+			}
+		}
+	}
+}
+
+// Drain return an iterator that pops values from the heap as it iterates, until the heap is empty.
+// Values left when the iteration is stopped stay in the heap.
+// Unlike [Heap.All] and [Heap.Sorted], the heap is not locked while the loop body runs,
+// so values pushed meanwhile are also popped in their turn.
+//
+//	for val := range heap.Drain() {
+//	   // code goes here
+//	}
+func (h *Heap[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			value, err := h.Pop()
+			if err != nil || !yield(value) {
+				return
+			}
+		}
+	}
+}
+
 // Peek at the value at the root node without removing it from the Heap.
 // Returns [ErrIsEmpty] if the [Heap] is empty.
 func (h *Heap[T]) Top() (T, error) {
@@ -144,24 +457,37 @@ func (h *Heap[T]) isEmpty() bool {
 	return len(h.values) == 0
 }
 
-// Swim/Heapify-up swim the bottom node toward the root.
-func (h *Heap[T]) swim() {
-	currIDX := len(h.values) - 1
-	curr := h.values[currIDX]
+// Build the heap bottom-up, by sinking every node that has a child starting from the last one.
+func (h *Heap[T]) heapify() {
+	if len(h.values) < 2 {
+		return
+	}
+	for i := h.getParentIDX(len(h.values) - 1); i >= 0; i-- {
+		h.sink(i)
+	}
+}
+
+// Move the node at the given index to it apporiate place, after its value is changed.
+func (h *Heap[T]) fix(idx int) {
+	h.sink(h.swim(idx))
+}
+
+// Swim/Heapify-up swim the node at the given index toward the root.
+// Returns the index the node ends up at.
+func (h *Heap[T]) swim(currIDX int) int {
 	for currIDX > 0 {
 		parentIDX := h.getParentIDX(currIDX)
-		parent := h.values[parentIDX]
-		if !h.cmp(curr, parent) {
-			return
+		if !h.cmp(h.values[currIDX], h.values[parentIDX]) {
+			break
 		}
-		h.values[parentIDX], h.values[currIDX] = curr, parent
+		h.swap(currIDX, parentIDX)
 		currIDX = parentIDX
 	}
+	return currIDX
 }
 
-// Sink/Heapify-down sink the root down to toward the bottom.
-func (h *Heap[T]) sink() {
-	currIDX := 0
+// Sink/Heapify-down sink the node at the given index down to toward the bottom.
+func (h *Heap[T]) sink(currIDX int) {
 	for currIDX < len(h.values) {
 		if childIDX, ok := h.getChildToSwap(currIDX); !ok {
 			return
@@ -173,49 +499,57 @@ func (h *Heap[T]) sink() {
 	}
 }
 
+// Swap two nodes, along with their handles.
+func (h *Heap[T]) swap(i, j int) {
+	h.values[i], h.values[j] = h.values[j], h.values[i]
+	if h.items != nil {
+		h.items[i], h.items[j] = h.items[j], h.items[i]
+		if h.items[i] != nil {
+			h.items[i].index = i
+		}
+		if h.items[j] != nil {
+			h.items[j].index = j
+		}
+	}
+}
+
 // Get the index of the better child to swap
 // If is a max heap get the max child, else if a min heap get min child.
 // If there is no child to swap then ok is false.
 func (h *Heap[T]) getChildToSwap(parentIDX int) (index int, ok bool) {
-	leftIDX := h.getLeftIDX(parentIDX)
+	firstIDX, endIDX := h.getChildrenIDX(parentIDX)
 
-	// Because leftIDX always less than rightIDX,
-	// so if leftIDX is greater than len(h.values)-1
-	// then there is no need to check for rightIDX.
-	if leftIDX >= len(h.values) {
+	// The node is a leaf
+	if firstIDX >= endIDX {
 		return 0, false
 	}
 
-	rightIDX := h.getRightIDX(parentIDX)
-	if rightIDX >= len(h.values) {
-		return leftIDX, true
-	}
-
-	if h.cmp(h.values[leftIDX], h.values[rightIDX]) {
-		return leftIDX, true
+	// Pick the child that satisfies cmp against all its siblings
+	index = firstIDX
+	for childIDX := firstIDX + 1; childIDX < endIDX; childIDX++ {
+		if !h.cmp(h.values[index], h.values[childIDX]) {
+			index = childIDX
+		}
 	}
-	return rightIDX, true
+	return index, true
 }
 
 // Only swap when h.cmp condition is NOT satisfied.
 func (h *Heap[T]) trySwap(parentIDX, childIDX int) bool {
-	child := h.values[childIDX]
-	parent := h.values[parentIDX]
-	if h.cmp(parent, child) {
+	if h.cmp(h.values[parentIDX], h.values[childIDX]) {
 		return false
 	}
-	h.values[childIDX], h.values[parentIDX] = parent, child
+	h.swap(parentIDX, childIDX)
 	return true
 }
 
 func (h *Heap[T]) getParentIDX(idx int) int {
-	return (idx - 1) / 2
-}
-
-func (h *Heap[T]) getLeftIDX(idx int) int {
-	return idx*2 + 1
+	return (idx - 1) / h.d
 }
 
-func (h *Heap[T]) getRightIDX(idx int) int {
-	return idx*2 + 2
+// Get the range [firstIDX, endIDX) of the children of a node that are in the heap.
+func (h *Heap[T]) getChildrenIDX(idx int) (firstIDX, endIDX int) {
+	firstIDX = min(idx*h.d+1, len(h.values))
+	endIDX = min(firstIDX+h.d, len(h.values))
+	return firstIDX, endIDX
 }
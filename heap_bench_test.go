@@ -0,0 +1,148 @@
+package collection_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/internal"
+)
+
+const (
+	benchHeapSize  = 1 << 20
+	benchMergeSize = 1 << 12
+)
+
+var benchHeapArities = []int{2, 4, 8}
+
+func BenchmarkHeapPushOneByOne(b *testing.B) {
+	values := rand.Perm(benchHeapSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap := collection.MustNewHeap[int](collection.LessThan)
+		for _, value := range values {
+			heap.Push(value)
+		}
+	}
+}
+
+func BenchmarkNewHeapFrom(b *testing.B) {
+	values := rand.Perm(benchHeapSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = collection.MustNewHeapFrom(collection.LessThan, values)
+	}
+}
+
+// Push values that are mostly smaller than the ones in the heap, so they swim up,
+// then pop a value for every eight pushes.
+func BenchmarkDaryHeapPushHeavy(b *testing.B) {
+	for _, d := range benchHeapArities {
+		b.Run(fmt.Sprintf("d=%d", d), func(b *testing.B) {
+			heap := collection.MustNewDaryHeap[int](d, collection.LessThan)
+			heap.Push(rand.Perm(benchHeapSize)...)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				heap.Push(-i)
+				if i%8 == 0 {
+					_, _ = heap.Pop()
+				}
+			}
+		})
+	}
+}
+
+// Pop a value then push a random one, so the heap keeps its size and every pop sinks a node to the bottom.
+func BenchmarkDaryHeapPopHeavy(b *testing.B) {
+	for _, d := range benchHeapArities {
+		b.Run(fmt.Sprintf("d=%d", d), func(b *testing.B) {
+			heap := collection.MustNewDaryHeap[int](d, collection.LessThan)
+			heap.Push(rand.Perm(benchHeapSize)...)
+			values := rand.Perm(benchHeapSize)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = heap.Pop()
+				heap.Push(values[i%benchHeapSize])
+			}
+		})
+	}
+}
+
+// Push a random value then pop the root, on a heap that already holds many values.
+func benchmarkHeapPushPop(b *testing.B, heap internal.Heap[int]) {
+	heap.Push(rand.Perm(benchHeapSize >> 4)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(rand.Int())
+		_, _ = heap.Pop()
+	}
+}
+
+// Decrease the value of a random handle, like in Dijkstra, on a heap that already holds many values.
+func benchmarkHeapDecreaseKey[I any](b *testing.B, heap testHandleHeap[I]) {
+	items := make([]I, benchHeapSize>>4)
+	for i := range items {
+		items[i] = heap.PushItem(rand.Int())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = heap.Update(items[rand.Intn(len(items))], -i)
+	}
+}
+
+func BenchmarkHeapPushPop(b *testing.B) {
+	b.Run("Heap", func(b *testing.B) {
+		benchmarkHeapPushPop(b, collection.MustNewHeap[int](collection.LessThan))
+	})
+	b.Run("PairingHeap", func(b *testing.B) {
+		benchmarkHeapPushPop(b, collection.MustNewPairingHeap[int](collection.LessThan))
+	})
+	b.Run("FibonacciHeap", func(b *testing.B) {
+		benchmarkHeapPushPop(b, collection.MustNewFibonacciHeap[int](collection.LessThan))
+	})
+}
+
+func BenchmarkHeapDecreaseKey(b *testing.B) {
+	b.Run("Heap", func(b *testing.B) {
+		benchmarkHeapDecreaseKey(b, collection.MustNewHeap[int](collection.LessThan))
+	})
+	b.Run("PairingHeap", func(b *testing.B) {
+		benchmarkHeapDecreaseKey(b, collection.MustNewPairingHeap[int](collection.LessThan))
+	})
+	b.Run("FibonacciHeap", func(b *testing.B) {
+		benchmarkHeapDecreaseKey(b, collection.MustNewFibonacciHeap[int](collection.LessThan))
+	})
+}
+
+// Move all the values of a heap into another, back and forth.
+func BenchmarkHeapMerge(b *testing.B) {
+	values := rand.Perm(benchMergeSize)
+	b.Run("Heap", func(b *testing.B) {
+		heap := collection.MustNewHeapFrom(collection.LessThan, values)
+		other := collection.MustNewHeap[int](collection.LessThan)
+		for i := 0; i < b.N; i++ {
+			other.Merge(heap)
+			heap.Clear()
+			heap, other = other, heap
+		}
+	})
+	b.Run("PairingHeap", func(b *testing.B) {
+		heap := collection.MustNewPairingHeap[int](collection.LessThan)
+		heap.Push(values...)
+		other := collection.MustNewPairingHeap[int](collection.LessThan)
+		for i := 0; i < b.N; i++ {
+			other.Meld(heap)
+			heap, other = other, heap
+		}
+	})
+	b.Run("FibonacciHeap", func(b *testing.B) {
+		heap := collection.MustNewFibonacciHeap[int](collection.LessThan)
+		heap.Push(values...)
+		other := collection.MustNewFibonacciHeap[int](collection.LessThan)
+		for i := 0; i < b.N; i++ {
+			other.Meld(heap)
+			heap, other = other, heap
+		}
+	})
+}
@@ -36,6 +36,43 @@ func TestHeapRace(t *testing.T) {
 			}
 		},
 
+		// Update and remove the values of handles
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				item := heap.PushItem(rand.Int())
+				_ = heap.Update(item, rand.Int())
+				_, _ = heap.Remove(item)
+			}
+		},
+
+		// Bulk operations on the heap
+		func() {
+			defer wg.Done()
+			other := collection.MustNewHeapFrom(collection.GreaterThan, rand.Perm(randint(10, 100)))
+			for i := 0; i < randint(10, 100); i++ {
+				heap.Merge(other)
+				_ = heap.PopN(randint(10, 100))
+				_, _ = heap.Replace(rand.Int())
+				_ = heap.Len()
+			}
+			heap.Clear()
+		},
+
+		// Iterate over the heap
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 100); i++ {
+				for range heap.All() {
+				}
+				for range heap.Sorted() {
+				}
+				for range heap.Drain() {
+					break
+				}
+			}
+		},
+
 		// Peek at the heap
 		func() {
 			defer wg.Done()
@@ -59,3 +96,90 @@ func TestHeapRace(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestMeldableHeapRace(t *testing.T) {
+	tests := []func(){
+		meldableHeapRaceTest(collection.MustNewPairingHeap[int]),
+		meldableHeapRaceTest(collection.MustNewFibonacciHeap[int]),
+	}
+	for _, test := range tests {
+		test()
+	}
+}
+
+// Create the race test of a meldable heap from its constructor.
+func meldableHeapRaceTest[H testMeldableHeap[H, I], I comparable](mustNewHeap func(cmp func(current, other int) bool) H) func() {
+	return func() {
+		var wg sync.WaitGroup
+		heap := mustNewHeap(collection.GreaterThan)
+		other := mustNewHeap(collection.GreaterThan)
+		functions := []func(){
+			// Push to the heap
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					heap.Push(rand.Int())
+				}
+			},
+
+			// Pop from the heap
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_, _ = heap.Pop()
+				}
+			},
+
+			// PushPop on the heap
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_, _ = heap.PushPop(rand.Int())
+				}
+			},
+
+			// Update and remove the values of handles, which may be melded into the other heap meanwhile
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					item := heap.PushItem(rand.Int())
+					_ = heap.Update(item, rand.Int())
+					_ = other.Update(item, rand.Int())
+					_, _ = heap.Remove(item)
+				}
+			},
+
+			// Meld the heaps into each other
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					heap.Meld(other)
+					other.Meld(heap)
+				}
+			},
+
+			// Peek at the heap
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_, _ = heap.Top()
+					_ = heap.Len()
+				}
+			},
+
+			// Check if heap is empty
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_ = heap.IsEmpty()
+				}
+			},
+		}
+
+		wg.Add(len(functions))
+		for _, f := range functions {
+			go f()
+		}
+		wg.Wait()
+	}
+}
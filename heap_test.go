@@ -1,11 +1,15 @@
 package collection_test
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/trviph/collection"
+	"github.com/trviph/collection/internal"
 )
 
 func TestNewHeap(t *testing.T) {
@@ -62,7 +66,7 @@ func TestMinHeap(t *testing.T) {
 	}
 }
 
-func minHeapTest(heap *collection.Heap[int]) error {
+func minHeapTest(heap internal.Heap[int]) error {
 	pushed := 0
 	for i := 0; i < 1000; i++ {
 		heap.Push(rand.Int())
@@ -113,7 +117,7 @@ func TestMaxHeap(t *testing.T) {
 	}
 }
 
-func maxHeapTest(heap *collection.Heap[int]) error {
+func maxHeapTest(heap internal.Heap[int]) error {
 	pushed := 0
 	for i := 0; i < 1000; i++ {
 		heap.Push(rand.Int())
@@ -211,3 +215,677 @@ func TestHeapTop(t *testing.T) {
 		}
 	}
 }
+
+func TestHeapUpdate(t *testing.T) {
+	minHeap := collection.MustNewHeap[int](collection.LessThan)
+	minHeap.Push(50, 60, 70)
+	items := make([]*collection.HeapItem[int], 100)
+	for i := range items {
+		items[i] = minHeap.PushItem(i)
+	}
+	minHeap.Push(80, 90)
+
+	// Move every handled value to a random place, and keep track of where it should be
+	want := []int{50, 60, 70, 80, 90}
+	for _, item := range items {
+		value := rand.Intn(1000)
+		if err := minHeap.Update(item, value); err != nil {
+			t.Errorf(testFailedMsg, "TestHeapUpdate", "nil error", err)
+		}
+		want = append(want, value)
+	}
+	slices.Sort(want)
+
+	for _, value := range want {
+		if got, err := minHeap.Pop(); err != nil {
+			t.Errorf(testFailedMsg, "TestHeapUpdate", "nil error", err)
+		} else if got != value {
+			t.Errorf(testFailedMsg, "TestHeapUpdate", value, got)
+		}
+	}
+
+	// The values have left the heap, the handles are no longer valid
+	for _, item := range items {
+		if err := minHeap.Update(item, 0); !errors.Is(err, collection.ErrNotFound) {
+			t.Errorf(testFailedMsg, "TestHeapUpdate", collection.ErrNotFound, err)
+		}
+	}
+}
+
+func TestHeapUpdateDecreaseKey(t *testing.T) {
+	// Dijkstra on a small graph, using Update to decrease the distance of a vertex
+	type vertex struct {
+		id   int
+		dist int
+	}
+	graph := map[int]map[int]int{
+		0: {1: 4, 2: 1},
+		1: {3: 1},
+		2: {1: 2, 3: 5},
+		3: {4: 3},
+		4: {},
+	}
+	minHeap := collection.MustNewHeap(func(a, b vertex) bool { return a.dist < b.dist })
+	items := make(map[int]*collection.HeapItem[vertex])
+	dist := map[int]int{0: 0}
+	items[0] = minHeap.PushItem(vertex{0, 0})
+	for !minHeap.IsEmpty() {
+		curr, _ := minHeap.Pop()
+		for next, weight := range graph[curr.id] {
+			d := curr.dist + weight
+			if old, ok := dist[next]; ok && old <= d {
+				continue
+			}
+			dist[next] = d
+			if err := minHeap.Update(items[next], vertex{next, d}); err != nil {
+				items[next] = minHeap.PushItem(vertex{next, d})
+			}
+		}
+	}
+
+	want := map[int]int{0: 0, 1: 3, 2: 1, 3: 4, 4: 7}
+	if !maps.Equal(dist, want) {
+		t.Errorf(testFailedMsg, "TestHeapUpdateDecreaseKey", want, dist)
+	}
+}
+
+func TestHeapRemove(t *testing.T) {
+	maxHeap := collection.MustNewHeap[int](collection.GreaterThan)
+	items := make(map[int]*collection.HeapItem[int])
+	for _, i := range rand.Perm(100) {
+		items[i] = maxHeap.PushItem(i)
+	}
+
+	// Remove the odd values
+	for i := 1; i < 100; i += 2 {
+		if got, err := maxHeap.Remove(items[i]); err != nil {
+			t.Errorf(testFailedMsg, "TestHeapRemove", "nil error", err)
+		} else if got != i {
+			t.Errorf(testFailedMsg, "TestHeapRemove", i, got)
+		}
+	}
+
+	// Should return error since the value is already removed
+	if _, err := maxHeap.Remove(items[1]); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestHeapRemove", collection.ErrNotFound, err)
+	}
+
+	// The even values are left in order
+	for want := 98; want >= 0; want -= 2 {
+		if got, err := maxHeap.Pop(); err != nil {
+			t.Errorf(testFailedMsg, "TestHeapRemove", "nil error", err)
+		} else if got != want {
+			t.Errorf(testFailedMsg, "TestHeapRemove", want, got)
+		}
+	}
+}
+
+func TestHeapInvalidItem(t *testing.T) {
+	maxHeap := collection.MustNewHeap[int](collection.GreaterThan)
+	otherHeap := collection.MustNewHeap[int](collection.GreaterThan)
+	item := otherHeap.PushItem(1)
+
+	// Should return error since the handle is nil
+	if err := maxHeap.Update(nil, 1); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestHeapInvalidItem", collection.ErrNotFound, err)
+	}
+	// Should return error since the handle belongs to another heap
+	if _, err := maxHeap.Remove(item); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestHeapInvalidItem", collection.ErrNotFound, err)
+	}
+
+	// Should return error since the value is replaced by PushPop
+	if _, err := otherHeap.PushPop(0); err != nil {
+		t.Errorf(testFailedMsg, "TestHeapInvalidItem", "nil error", err)
+	}
+	if err := otherHeap.Update(item, 3); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestHeapInvalidItem", collection.ErrNotFound, err)
+	}
+}
+
+func TestNewHeapFrom(t *testing.T) {
+	if _, err := collection.NewHeapFrom[int](nil, nil); err == nil {
+		t.Errorf(testFailedMsg, "TestNewHeapFrom", "error", nil)
+	}
+
+	for _, size := range []int{0, 1, 2, 3, 100, randint(100, 1000)} {
+		values := rand.Perm(size)
+		minHeap := collection.MustNewHeapFrom(collection.LessThan, values)
+		if got := minHeap.Len(); got != size {
+			t.Errorf(testFailedMsg, "TestNewHeapFrom", size, got)
+		}
+
+		// The heap has its own copy of the values
+		slices.Reverse(values)
+		for want := 0; want < size; want++ {
+			if got, err := minHeap.Pop(); err != nil {
+				t.Errorf(testFailedMsg, "TestNewHeapFrom", "nil error", err)
+			} else if got != want {
+				t.Errorf(testFailedMsg, "TestNewHeapFrom", want, got)
+			}
+		}
+	}
+}
+
+func TestMustNewHeapFrom(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewHeapFrom", "panic", r)
+		}
+	}()
+	_ = collection.MustNewHeapFrom[int](nil, nil)
+}
+
+func TestHeapPushMany(t *testing.T) {
+	maxHeap := collection.MustNewHeap[int](collection.GreaterThan)
+	item := maxHeap.PushItem(-1)
+
+	// More values than the heap holds, the heap is rebuilt
+	maxHeap.Push(rand.Perm(100)...)
+	// Fewer values than the heap holds, the values are pushed one by one
+	maxHeap.Push(100, 101)
+
+	// The handle is still valid after the heap is rebuilt
+	if err := maxHeap.Update(item, 102); err != nil {
+		t.Errorf(testFailedMsg, "TestHeapPushMany", "nil error", err)
+	}
+	for want := 102; want >= 0; want-- {
+		if got, err := maxHeap.Pop(); err != nil {
+			t.Errorf(testFailedMsg, "TestHeapPushMany", "nil error", err)
+		} else if got != want {
+			t.Errorf(testFailedMsg, "TestHeapPushMany", want, got)
+		}
+	}
+}
+
+func TestHeapReplace(t *testing.T) {
+	minHeap := collection.MustNewHeap[int](collection.LessThan)
+
+	// Should return error since the heap is empty, and not push the value
+	if _, err := minHeap.Replace(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestHeapReplace", collection.ErrIsEmpty, err)
+	}
+	if !minHeap.IsEmpty() {
+		t.Errorf(testFailedMsg, "TestHeapReplace", true, false)
+	}
+
+	minHeap.Push(2, 4)
+	// Should return 2 even though the new value is smaller, unlike PushPop
+	want := 2
+	if got, err := minHeap.Replace(1); err != nil {
+		t.Errorf(testFailedMsg, "TestHeapReplace", "nil error", err)
+	} else if got != want {
+		t.Errorf(testFailedMsg, "TestHeapReplace", want, got)
+	}
+
+	// Should return 1 and sink 5 below 4
+	want = 1
+	if got, err := minHeap.Replace(5); err != nil {
+		t.Errorf(testFailedMsg, "TestHeapReplace", "nil error", err)
+	} else if got != want {
+		t.Errorf(testFailedMsg, "TestHeapReplace", want, got)
+	}
+	if got := minHeap.PopN(2); !slices.Equal(got, []int{4, 5}) {
+		t.Errorf(testFailedMsg, "TestHeapReplace", []int{4, 5}, got)
+	}
+}
+
+func TestHeapPopN(t *testing.T) {
+	maxHeap := collection.MustNewHeapFrom(collection.GreaterThan, rand.Perm(10))
+
+	if got := maxHeap.PopN(0); len(got) != 0 {
+		t.Errorf(testFailedMsg, "TestHeapPopN", []int{}, got)
+	}
+	if got := maxHeap.PopN(-1); len(got) != 0 {
+		t.Errorf(testFailedMsg, "TestHeapPopN", []int{}, got)
+	}
+
+	want := []int{9, 8, 7}
+	if got := maxHeap.PopN(3); !slices.Equal(got, want) {
+		t.Errorf(testFailedMsg, "TestHeapPopN", want, got)
+	}
+
+	// Should return the remaining values since there are fewer than k
+	want = []int{6, 5, 4, 3, 2, 1, 0}
+	if got := maxHeap.PopN(100); !slices.Equal(got, want) {
+		t.Errorf(testFailedMsg, "TestHeapPopN", want, got)
+	}
+	if got := maxHeap.PopN(1); len(got) != 0 {
+		t.Errorf(testFailedMsg, "TestHeapPopN", []int{}, got)
+	}
+}
+
+func TestHeapMerge(t *testing.T) {
+	minHeap := collection.MustNewHeapFrom(collection.LessThan, []int{0, 2, 4, 6})
+	other := collection.MustNewHeapFrom(collection.LessThan, []int{1, 3, 5})
+
+	minHeap.Merge(other)
+	// The other heap is left unchanged
+	if got := other.Len(); got != 3 {
+		t.Errorf(testFailedMsg, "TestHeapMerge", 3, got)
+	}
+
+	// Merging a heap with itself doubles its values
+	other.Merge(other)
+	want := []int{1, 1, 3, 3, 5, 5}
+	if got := other.PopN(other.Len()); !slices.Equal(got, want) {
+		t.Errorf(testFailedMsg, "TestHeapMerge", want, got)
+	}
+
+	want = []int{0, 1, 2, 3, 4, 5, 6}
+	if got := minHeap.PopN(minHeap.Len()); !slices.Equal(got, want) {
+		t.Errorf(testFailedMsg, "TestHeapMerge", want, got)
+	}
+}
+
+func TestHeapClear(t *testing.T) {
+	minHeap := collection.MustNewHeap[int](collection.LessThan)
+	item := minHeap.PushItem(1)
+	minHeap.Push(2, 3)
+
+	minHeap.Clear()
+	if got := minHeap.Len(); got != 0 {
+		t.Errorf(testFailedMsg, "TestHeapClear", 0, got)
+	}
+	// Should return error since the value is cleared
+	if _, err := minHeap.Remove(item); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, "TestHeapClear", collection.ErrNotFound, err)
+	}
+
+	// The heap is still usable after clear
+	minHeap.Push(5, 4)
+	item = minHeap.PushItem(6)
+	if err := minHeap.Update(item, 3); err != nil {
+		t.Errorf(testFailedMsg, "TestHeapClear", "nil error", err)
+	}
+	want := []int{3, 4, 5}
+	if got := minHeap.PopN(3); !slices.Equal(got, want) {
+		t.Errorf(testFailedMsg, "TestHeapClear", want, got)
+	}
+}
+
+func TestHeapAll(t *testing.T) {
+	minHeap := collection.MustNewHeap[int](collection.LessThan)
+	for range minHeap.All() {
+		t.Errorf(testFailedMsg, "TestHeapAll", "no value", "a value")
+	}
+
+	values := rand.Perm(100)
+	minHeap.Push(values...)
+	got := slices.Collect(minHeap.All())
+	// The root is first, and every value is there
+	if got[0] != 0 {
+		t.Errorf(testFailedMsg, "TestHeapAll", 0, got[0])
+	}
+	slices.Sort(got)
+	slices.Sort(values)
+	if !slices.Equal(got, values) {
+		t.Errorf(testFailedMsg, "TestHeapAll", values, got)
+	}
+
+	// Should stop early
+	count := 0
+	for range minHeap.All() {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Errorf(testFailedMsg, "TestHeapAll", 10, count)
+	}
+}
+
+func TestHeapSorted(t *testing.T) {
+	maxHeap := collection.MustNewHeap[int](collection.GreaterThan)
+	for range maxHeap.Sorted() {
+		t.Errorf(testFailedMsg, "TestHeapSorted", "no value", "a value")
+	}
+
+	values := make([]int, randint(100, 1000))
+	for i := range values {
+		// Some values are equal
+		values[i] = rand.Intn(len(values) / 2)
+	}
+	maxHeap.Push(values...)
+	want := slices.Clone(values)
+	slices.Sort(want)
+	slices.Reverse(want)
+
+	if got := slices.Collect(maxHeap.Sorted()); !slices.Equal(got, want) {
+		t.Errorf(testFailedMsg, "TestHeapSorted", want, got)
+	}
+	// The heap is left unchanged
+	if got := maxHeap.Len(); got != len(values) {
+		t.Errorf(testFailedMsg, "TestHeapSorted", len(values), got)
+	}
+
+	// Should stop early with the top values
+	got := make([]int, 0, 5)
+	for value := range maxHeap.Sorted() {
+		got = append(got, value)
+		if len(got) == 5 {
+			break
+		}
+	}
+	if !slices.Equal(got, want[:5]) {
+		t.Errorf(testFailedMsg, "TestHeapSorted", want[:5], got)
+	}
+
+	if got := maxHeap.PopN(len(values)); !slices.Equal(got, want) {
+		t.Errorf(testFailedMsg, "TestHeapSorted", want, got)
+	}
+}
+
+func TestHeapDrain(t *testing.T) {
+	minHeap := collection.MustNewHeapFrom(collection.LessThan, rand.Perm(10))
+
+	// Should stop early and leave the remaining values
+	want := []int{0, 1, 2}
+	got := make([]int, 0, 3)
+	for value := range minHeap.Drain() {
+		got = append(got, value)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf(testFailedMsg, "TestHeapDrain", want, got)
+	}
+	if got := minHeap.Len(); got != 7 {
+		t.Errorf(testFailedMsg, "TestHeapDrain", 7, got)
+	}
+
+	// Values pushed while draining are popped in their turn
+	want = []int{3, 4, 4, 5, 6, 7, 8, 9}
+	got = got[:0]
+	for value := range minHeap.Drain() {
+		if value == 3 {
+			minHeap.Push(4)
+		}
+		got = append(got, value)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf(testFailedMsg, "TestHeapDrain", want, got)
+	}
+	if !minHeap.IsEmpty() {
+		t.Errorf(testFailedMsg, "TestHeapDrain", true, false)
+	}
+}
+
+func TestNewDaryHeap(t *testing.T) {
+	// Should return error since the arity is less than 2
+	for _, d := range []int{-1, 0, 1} {
+		if _, err := collection.NewDaryHeap[int](d, collection.LessThan); err == nil {
+			t.Errorf(testFailedMsg, "TestNewDaryHeap", "error", nil)
+		}
+	}
+	// Should return error since cmp is nil
+	if _, err := collection.NewDaryHeap[int](4, nil); err == nil {
+		t.Errorf(testFailedMsg, "TestNewDaryHeap", "error", nil)
+	}
+	if _, err := collection.NewDaryHeap[int](2, collection.LessThan); err != nil {
+		t.Errorf(testFailedMsg, "TestNewDaryHeap", "nil error", err)
+	}
+}
+
+func TestMustNewDaryHeap(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewDaryHeap", "panic", r)
+		}
+	}()
+	_ = collection.MustNewDaryHeap[int](1, collection.LessThan)
+}
+
+func TestDaryHeap(t *testing.T) {
+	for d := 2; d <= 8; d++ {
+		if err := minHeapTest(collection.MustNewDaryHeap[int](d, collection.LessThan)); err != nil {
+			t.Errorf(testFailedMsg, "TestDaryHeap", "nil error", err)
+		}
+		if err := maxHeapTest(collection.MustNewDaryHeap[int](d, collection.GreaterThan)); err != nil {
+			t.Errorf(testFailedMsg, "TestDaryHeap", "nil error", err)
+		}
+
+		// Rebuilding the heap, handles and sorted iteration use the same index math
+		minHeap := collection.MustNewDaryHeap[int](d, collection.LessThan)
+		items := make(map[int]*collection.HeapItem[int])
+		for i := 0; i < 10; i++ {
+			items[i] = minHeap.PushItem(i)
+		}
+		minHeap.Push(rand.Perm(100)...)
+		for i := 0; i < 10; i++ {
+			if err := minHeap.Update(items[i], 100+i); err != nil {
+				t.Errorf(testFailedMsg, "TestDaryHeap", "nil error", err)
+			}
+		}
+		if _, err := minHeap.Remove(items[0]); err != nil {
+			t.Errorf(testFailedMsg, "TestDaryHeap", "nil error", err)
+		}
+
+		want := make([]int, 0, 109)
+		for i := 0; i < 100; i++ {
+			want = append(want, i)
+		}
+		for i := 1; i < 10; i++ {
+			want = append(want, 100+i)
+		}
+		if got := slices.Collect(minHeap.Sorted()); !slices.Equal(got, want) {
+			t.Errorf(testFailedMsg, "TestDaryHeap", want, got)
+		}
+		if got := minHeap.PopN(minHeap.Len()); !slices.Equal(got, want) {
+			t.Errorf(testFailedMsg, "TestDaryHeap", want, got)
+		}
+	}
+}
+
+// A heap whose values can be updated and removed through handles of type I.
+type testHandleHeap[I any] interface {
+	internal.Heap[int]
+	Len() int
+	PushItem(value int) I
+	Update(item I, value int) error
+	Remove(item I) (int, error)
+}
+
+// A heap that can be melded with another heap of type H.
+type testMeldableHeap[H any, I any] interface {
+	testHandleHeap[I]
+	Meld(other H)
+}
+
+// Run the tests shared by every heap with handles, newHeap must create an empty min heap.
+func handleHeapTest[I any](t *testing.T, name string, newHeap func() testHandleHeap[I]) {
+	if err := minHeapTest(newHeap()); err != nil {
+		t.Errorf(testFailedMsg, name, "nil error", err)
+	}
+
+	heap := newHeap()
+	// Should return error since the heap is empty
+	if _, err := heap.Pop(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, name, collection.ErrIsEmpty, err)
+	}
+	if _, err := heap.Top(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, name, collection.ErrIsEmpty, err)
+	}
+	if _, err := heap.PushPop(1); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, name, collection.ErrIsEmpty, err)
+	}
+
+	// Should return the pushed value since it would be the root
+	heap.Push(2)
+	if got, err := heap.PushPop(1); err != nil || got != 1 {
+		t.Errorf(testFailedMsg, name, 1, got)
+	}
+	if got, err := heap.PushPop(3); err != nil || got != 2 {
+		t.Errorf(testFailedMsg, name, 2, got)
+	}
+	if got, err := heap.Top(); err != nil || got != 3 {
+		t.Errorf(testFailedMsg, name, 3, got)
+	}
+
+	// Random operations, checked against the values that should be in the heap.
+	// The values end with the index of their handle, so equal values can be told apart.
+	heap = newHeap()
+	items := make([]I, 0)
+	values := make(map[int]int)
+	live := make(map[int]bool)
+	for i := 0; i < 5000; i++ {
+		switch op := rand.Intn(10); {
+		case op < 4:
+			value := rand.Intn(1000)<<16 | len(items)
+			values[len(items)] = value
+			live[len(items)] = true
+			items = append(items, heap.PushItem(value))
+		case op < 7 && len(items) > 0:
+			// Update to a random value, which may go toward or away from the root
+			idx := rand.Intn(len(items))
+			value := rand.Intn(1000)<<16 | idx
+			err := heap.Update(items[idx], value)
+			if live[idx] && err != nil {
+				t.Errorf(testFailedMsg, name, "nil error", err)
+			} else if !live[idx] && !errors.Is(err, collection.ErrNotFound) {
+				t.Errorf(testFailedMsg, name, collection.ErrNotFound, err)
+			}
+			if live[idx] {
+				values[idx] = value
+			}
+		case op < 8 && len(items) > 0:
+			idx := rand.Intn(len(items))
+			got, err := heap.Remove(items[idx])
+			if live[idx] && (err != nil || got != values[idx]) {
+				t.Errorf(testFailedMsg, name, values[idx], got)
+			} else if !live[idx] && !errors.Is(err, collection.ErrNotFound) {
+				t.Errorf(testFailedMsg, name, collection.ErrNotFound, err)
+			}
+			delete(live, idx)
+		default:
+			want, wantErr := -1, error(collection.ErrIsEmpty)
+			for idx := range live {
+				if want < 0 || values[idx] < want {
+					want, wantErr = values[idx], nil
+				}
+			}
+			got, err := heap.Pop()
+			if !errors.Is(err, wantErr) || (err == nil && got != want) {
+				t.Fatalf(testFailedMsg, name, want, got)
+			}
+			delete(live, got&0xffff)
+		}
+		if got := heap.Len(); got != len(live) {
+			t.Fatalf(testFailedMsg, name, len(live), got)
+		}
+	}
+}
+
+// Run the tests shared by every meldable heap, newHeap must create an empty min heap.
+func meldHeapTest[H testMeldableHeap[H, I], I comparable](t *testing.T, name string, newHeap func() H) {
+	evens, odds := newHeap(), newHeap()
+	evenItems := make([]I, 0)
+	oddItems := make([]I, 0)
+	for i := 0; i < 100; i += 2 {
+		evenItems = append(evenItems, evens.PushItem(i+100))
+		oddItems = append(oddItems, odds.PushItem(i+101))
+	}
+
+	evens.Meld(odds)
+	if got := evens.Len(); got != 100 {
+		t.Errorf(testFailedMsg, name, 100, got)
+	}
+	if !odds.IsEmpty() {
+		t.Errorf(testFailedMsg, name, true, false)
+	}
+	// The handles of the melded heap now belong to the heap
+	if err := odds.Update(oddItems[0], 0); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, name, collection.ErrNotFound, err)
+	}
+
+	// Melding with itself or nothing does nothing
+	evens.Meld(evens)
+	var none H
+	evens.Meld(none)
+
+	// Meld again, so the handles of odds go through two melds
+	all := newHeap()
+	all.Meld(evens)
+	for i := range oddItems {
+		if err := all.Update(oddItems[i], 2*i+1); err != nil {
+			t.Errorf(testFailedMsg, name, "nil error", err)
+		}
+		if err := all.Update(evenItems[i], 2*i); err != nil {
+			t.Errorf(testFailedMsg, name, "nil error", err)
+		}
+	}
+	if _, err := all.Remove(oddItems[0]); err != nil {
+		t.Errorf(testFailedMsg, name, "nil error", err)
+	}
+
+	// The emptied heaps can still be used on their own
+	item := odds.PushItem(-1)
+	if err := all.Update(item, -1); !errors.Is(err, collection.ErrNotFound) {
+		t.Errorf(testFailedMsg, name, collection.ErrNotFound, err)
+	}
+	if got, err := odds.Pop(); err != nil || got != -1 {
+		t.Errorf(testFailedMsg, name, -1, got)
+	}
+
+	for want := 0; want < 100; want++ {
+		if want == 1 {
+			continue
+		}
+		if got, err := all.Pop(); err != nil {
+			t.Errorf(testFailedMsg, name, "nil error", err)
+		} else if got != want {
+			t.Errorf(testFailedMsg, name, want, got)
+		}
+	}
+	if !all.IsEmpty() {
+		t.Errorf(testFailedMsg, name, true, false)
+	}
+}
+
+func TestMeldableHeap(t *testing.T) {
+	tests := map[string]func(t *testing.T, name string){
+		"PairingHeap":   meldableHeapTest(collection.NewPairingHeap[int], collection.MustNewPairingHeap[int]),
+		"FibonacciHeap": meldableHeapTest(collection.NewFibonacciHeap[int], collection.MustNewFibonacciHeap[int]),
+	}
+	for name, test := range tests {
+		test(t, "TestMeldableHeap "+name)
+	}
+}
+
+// Create the tests of a meldable heap from its constructors.
+func meldableHeapTest[H testMeldableHeap[H, I], I comparable](
+	newHeap func(cmp func(current, other int) bool) (H, error),
+	mustNewHeap func(cmp func(current, other int) bool) H,
+) func(t *testing.T, name string) {
+	return func(t *testing.T, name string) {
+		if _, err := newHeap(nil); err == nil {
+			t.Errorf(testFailedMsg, name, "error", nil)
+		}
+		if _, err := newHeap(collection.LessThan); err != nil {
+			t.Errorf(testFailedMsg, name, "nil error", err)
+		}
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf(testFailedMsg, name, "panic", r)
+				}
+			}()
+			_ = mustNewHeap(nil)
+		}()
+
+		if err := minHeapTest(mustNewHeap(collection.LessThanOrEqual)); err != nil {
+			t.Errorf(testFailedMsg, name, "nil error", err)
+		}
+		if err := maxHeapTest(mustNewHeap(collection.GreaterThan)); err != nil {
+			t.Errorf(testFailedMsg, name, "nil error", err)
+		}
+		handleHeapTest(t, name, func() testHandleHeap[I] {
+			return mustNewHeap(collection.LessThan)
+		})
+		meldHeapTest(t, name, func() H {
+			return mustNewHeap(collection.LessThan)
+		})
+	}
+}
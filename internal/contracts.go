@@ -2,7 +2,10 @@ package internal
 
 // This file contains interfaces to guarantee backward compatibility of the package.
 // You should only add to this file and not change or delete in it.
-import "iter"
+import (
+	"context"
+	"iter"
+)
 
 type List[T any] interface {
 	Length() int
@@ -33,6 +36,32 @@ type Queue[T any] interface {
 	Rear() (T, error)
 }
 
+type BlockingQueue[T any] interface {
+	Queue[T]
+	DequeueWait(ctx context.Context) (T, error)
+	Close() error
+}
+
+type BoundedQueue[T any] interface {
+	BlockingQueue[T]
+	TryPush(values ...T) error
+	PushWait(ctx context.Context, values ...T) error
+	Cap() int
+}
+
+type Deque[T any] interface {
+	Length() int
+	PushFront(values ...T)
+	PushBack(values ...T)
+	PopFront() (T, error)
+	PopBack() (T, error)
+	Front() (T, error)
+	Back() (T, error)
+	At(i int) (T, error)
+	All() iter.Seq2[int, T]
+	Backward() iter.Seq2[int, T]
+}
+
 type Heap[T any] interface {
 	Push(values ...T)
 	Pop() (T, error)
@@ -41,7 +70,42 @@ type Heap[T any] interface {
 	IsEmpty() bool
 }
 
+type MinMaxHeap[T any] interface {
+	Heap[T]
+	Len() int
+	Min() (T, error)
+	Max() (T, error)
+	PopMin() (T, error)
+	PopMax() (T, error)
+}
+
 type Cache[K comparable, T any] interface {
 	Put(key K, value T)
 	Get(key K) (T, error)
+	Delete(key K) bool
+	Contains(key K) bool
+	Len() int
+	Cap() int
+	Clear()
+	TryPut(key K, value T) error
+	Cost() int64
+	GetOrLoad(ctx context.Context, key K, loader func(ctx context.Context, key K) (T, error)) (T, error)
+	ResetStats()
+	All() iter.Seq2[K, T]
+	Backward() iter.Seq2[K, T]
+	Keys() iter.Seq[K]
+	Values() iter.Seq[T]
+}
+
+type CountMinSketch interface {
+	Add(hash uint64)
+	Estimate(hash uint64) int
+	Halve()
+	Reset()
+}
+
+type BloomFilter interface {
+	Add(hash uint64)
+	Contains(hash uint64) bool
+	Reset()
 }
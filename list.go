@@ -380,7 +380,7 @@ func (l *List[T]) isEmpty() bool {
 
 // Return the head node of the [List].
 //
-// BUG(trviph): This function is needed by the cache package,
+// BUG(trviph): This function and the other node functions below are needed by the cache package,
 // but is leaks [internal.Node] to the users.
 // We could either find a way to remove this
 // or could just ignore this.
@@ -389,3 +389,61 @@ func (l *List[T]) Head() *internal.Node[T] {
 	defer l.mu.RUnlock()
 	return l.head
 }
+
+// Return the tail node of the [List].
+func (l *List[T]) Tail() *internal.Node[T] {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.tail
+}
+
+// MoveToFront moves a node of the [List] to the head of the list in O(1).
+// The node must belong to this list, else the list will be corrupted.
+func (l *List[T]) MoveToFront(node *internal.Node[T]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if node == l.head {
+		return
+	}
+	if node == l.tail {
+		l.tail = node.Left
+	}
+	node.Unlink()
+	node.Insert(l.head)
+	l.head = node
+}
+
+// RemoveNode removes a node from the [List] in O(1).
+// The node must belong to this list, else the list will be corrupted.
+func (l *List[T]) RemoveNode(node *internal.Node[T]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch node {
+	case l.head:
+		_, _ = l.dequeue()
+	case l.tail:
+		_, _ = l.pop()
+	default:
+		node.Unlink()
+		l.length--
+	}
+}
+
+// InsertAfter adds a new node after a node of the [List] in O(1), and returns the new node.
+// The node must belong to this list, else the list will be corrupted.
+func (l *List[T]) InsertAfter(node *internal.Node[T], value T) *internal.Node[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if node == l.tail {
+		l.append(value)
+		return l.tail
+	}
+
+	newNode := &internal.Node[T]{Value: value}
+	node.Insert(newNode)
+	l.length++
+	return newNode
+}
@@ -379,3 +379,103 @@ func TestListRemove(t *testing.T) {
 		t.Errorf(testFailedMsg, "TestListRemove", collection.ErrIsEmpty, gotErr)
 	}
 }
+
+func TestListMoveToFront(t *testing.T) {
+	list := collection.NewList(1, 2, 3, 4, 5)
+
+	// Move the tail to the front, this make the list become [5, 1, 2, 3, 4]
+	list.MoveToFront(list.Tail())
+	// Move the head to the front, this should do nothing
+	list.MoveToFront(list.Head())
+	// Move a middle node to the front, this make the list become [2, 5, 1, 3, 4]
+	list.MoveToFront(list.Head().Right.Right)
+
+	want := []int{2, 5, 1, 3, 4}
+	// Test to see if nodes are linked properly
+	for idx, got := range list.All() {
+		if want[idx] != got {
+			t.Errorf(testFailedMsg, "TestListMoveToFront", want[idx], got)
+		}
+	}
+
+	// Test to see if nodes are linked properly
+	for idx, got := range list.Backward() {
+		if want[idx] != got {
+			t.Errorf(testFailedMsg, "TestListMoveToFront", want[idx], got)
+		}
+	}
+
+	if list.Length() != len(want) {
+		t.Errorf(testFailedMsg, "TestListMoveToFront", len(want), list.Length())
+	}
+}
+
+func TestListRemoveNode(t *testing.T) {
+	list := collection.NewList(1, 2, 3, 4, 5)
+
+	// Remove the head, this make the list become [2, 3, 4, 5]
+	list.RemoveNode(list.Head())
+	// Remove the tail, this make the list become [2, 3, 4]
+	list.RemoveNode(list.Tail())
+	// Remove a middle node, this make the list become [2, 4]
+	list.RemoveNode(list.Head().Right)
+
+	want := []int{2, 4}
+	// Test to see if nodes are linked properly
+	for idx, got := range list.All() {
+		if want[idx] != got {
+			t.Errorf(testFailedMsg, "TestListRemoveNode", want[idx], got)
+		}
+	}
+
+	// Test to see if nodes are linked properly
+	for idx, got := range list.Backward() {
+		if want[idx] != got {
+			t.Errorf(testFailedMsg, "TestListRemoveNode", want[idx], got)
+		}
+	}
+
+	// Remove the last nodes, this make the list empty
+	list.RemoveNode(list.Tail())
+	list.RemoveNode(list.Head())
+	if list.Length() != 0 {
+		t.Errorf(testFailedMsg, "TestListRemoveNode", 0, list.Length())
+	}
+	if list.Head() != nil || list.Tail() != nil {
+		t.Errorf(testFailedMsg, "TestListRemoveNode", "nil head and tail", "non-nil")
+	}
+}
+
+func TestListInsertAfter(t *testing.T) {
+	list := collection.NewList(1, 3, 5)
+
+	// Insert after the tail, this make the list become [1, 3, 5, 6]
+	if node := list.InsertAfter(list.Tail(), 6); node != list.Tail() {
+		t.Errorf(testFailedMsg, "TestListInsertAfter", "new tail", node)
+	}
+	// Insert after the head, this make the list become [1, 2, 3, 5, 6]
+	node := list.InsertAfter(list.Head(), 2)
+	// Insert after the new node, this make the list become [1, 2, 2, 3, 5, 6]
+	node = list.InsertAfter(node, 2)
+	// Insert after a middle node, this make the list become [1, 2, 2, 3, 4, 5, 6]
+	list.InsertAfter(node.Right, 4)
+
+	want := []int{1, 2, 2, 3, 4, 5, 6}
+	// Test to see if nodes are linked properly
+	for idx, got := range list.All() {
+		if want[idx] != got {
+			t.Errorf(testFailedMsg, "TestListInsertAfter", want[idx], got)
+		}
+	}
+
+	// Test to see if nodes are linked properly
+	for idx, got := range list.Backward() {
+		if want[idx] != got {
+			t.Errorf(testFailedMsg, "TestListInsertAfter", want[idx], got)
+		}
+	}
+
+	if list.Length() != len(want) {
+		t.Errorf(testFailedMsg, "TestListInsertAfter", len(want), list.Length())
+	}
+}
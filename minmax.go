@@ -0,0 +1,292 @@
+package collection
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/trviph/collection/internal"
+)
+
+// A [MinMaxHeap] implemented by using slice as the base, a double-ended priority queue
+// where both the smallest and the largest value can be peeked in O(1) and popped in O(log n).
+// The levels of the tree alternate between min levels, starting with the root,
+// whose nodes are the smallest of their subtree, and max levels whose nodes are the largest of their subtree.
+// MinMaxHeap is thread-safe, because it only allow one goroutine at a time to access it data.
+//
+// Values are compared like in [NewHeap], so the min end is the value a [Heap] with the same cmp would pop first,
+// and the max end is the one it would pop last. Pop and Top work on the min end.
+type MinMaxHeap[T any] struct {
+	mu     sync.RWMutex
+	values []T
+	cmp    func(T, T) bool
+}
+
+// Interface guard
+var (
+	_ internal.Heap[any]       = (*MinMaxHeap[any])(nil)
+	_ internal.MinMaxHeap[any] = (*MinMaxHeap[any])(nil)
+)
+
+// [NewMinMaxHeap] creates a new [MinMaxHeap], see [NewHeap] for cmp.
+// This will return an error if cmp is nil, if you want to panic instead use [MustNewMinMaxHeap].
+//
+//	heap, err := collection.NewMinMaxHeap(collection.LessThan[int])
+func NewMinMaxHeap[T any](cmp func(current, other T) bool) (*MinMaxHeap[T], error) {
+	if cmp == nil {
+		return nil, fmt.Errorf("function argument is required to create a new min-max heap")
+	}
+
+	return &MinMaxHeap[T]{values: make([]T, 0), cmp: cmp}, nil
+}
+
+// Like [NewMinMaxHeap] but will panic if cmp is nil.
+func MustNewMinMaxHeap[T any](cmp func(current, other T) bool) *MinMaxHeap[T] {
+	return Must(func() (*MinMaxHeap[T], error) {
+		return NewMinMaxHeap(cmp)
+	})
+}
+
+// Push values into the heap.
+func (h *MinMaxHeap[T]) Push(values ...T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, value := range values {
+		h.values = append(h.values, value)
+		h.pushUp(len(h.values) - 1)
+	}
+}
+
+// Pop is the same as [MinMaxHeap.PopMin].
+func (h *MinMaxHeap[T]) Pop() (T, error) {
+	return h.PopMin()
+}
+
+// PopMin gets the value at the min end, and removes it from the heap.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *MinMaxHeap[T]) PopMin() (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.values) == 0 {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to pop min on min-max heap, cause %w", ErrIsEmpty)
+	}
+	return h.removeAt(0), nil
+}
+
+// PopMax gets the value at the max end, and removes it from the heap.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *MinMaxHeap[T]) PopMax() (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.values) == 0 {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to pop max on min-max heap, cause %w", ErrIsEmpty)
+	}
+	return h.removeAt(h.maxIDX()), nil
+}
+
+// Push a value into the heap and then pop the value at the min end.
+// This function is equivalent to call a [MinMaxHeap.Push] followed by a [MinMaxHeap.PopMin],
+// but have a more efficient implementation.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *MinMaxHeap[T]) PushPop(value T) (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var res T
+	if len(h.values) == 0 {
+		return res, fmt.Errorf("failed to push and pop on min-max heap, cause %w", ErrIsEmpty)
+	}
+
+	// The value would be the new min, so it is popped right away
+	if h.cmp(value, h.values[0]) {
+		return value, nil
+	}
+	res = h.values[0]
+	h.values[0] = value
+	h.pushDown(0)
+	return res, nil
+}
+
+// Top is the same as [MinMaxHeap.Min].
+func (h *MinMaxHeap[T]) Top() (T, error) {
+	return h.Min()
+}
+
+// Min peeks at the value at the min end without removing it from the heap.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *MinMaxHeap[T]) Min() (T, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.values) == 0 {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to peek at min of min-max heap, cause %w", ErrIsEmpty)
+	}
+	return h.values[0], nil
+}
+
+// Max peeks at the value at the max end without removing it from the heap.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *MinMaxHeap[T]) Max() (T, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.values) == 0 {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to peek at max of min-max heap, cause %w", ErrIsEmpty)
+	}
+	return h.values[h.maxIDX()], nil
+}
+
+// IsEmpty returns true if the heap does not hold any value.
+func (h *MinMaxHeap[T]) IsEmpty() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.values) == 0
+}
+
+// Len returns the number of values in the heap.
+func (h *MinMaxHeap[T]) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.values)
+}
+
+// Get the index of the max end, which is the root if it is alone, else the largest of its children.
+func (h *MinMaxHeap[T]) maxIDX() int {
+	switch len(h.values) {
+	case 1:
+		return 0
+	case 2:
+		return 1
+	}
+	if h.less(1, 2) {
+		return 2
+	}
+	return 1
+}
+
+// Remove the value at the given index, and return it.
+func (h *MinMaxHeap[T]) removeAt(idx int) T {
+	last := len(h.values) - 1
+	res := h.values[idx]
+	h.values[idx] = h.values[last]
+
+	// Shorten the underlying array
+	var zeroValue T
+	h.values[last] = zeroValue
+	h.values = h.values[:last]
+
+	// Push the moved node down to it apporiate place
+	if idx < last {
+		h.pushDown(idx)
+	}
+	return res
+}
+
+// Push the node at the given index up toward the root, through the levels of its own kind.
+func (h *MinMaxHeap[T]) pushUp(idx int) {
+	if idx == 0 {
+		return
+	}
+	parentIDX := (idx - 1) / 2
+	if h.isMinLevel(idx) {
+		if h.less(parentIDX, idx) {
+			// The node is larger than its max parent, so it belongs to the max levels
+			h.swap(idx, parentIDX)
+			h.pushUpLevels(parentIDX, h.greater)
+		} else {
+			h.pushUpLevels(idx, h.less)
+		}
+	} else {
+		if h.less(idx, parentIDX) {
+			// The node is smaller than its min parent, so it belongs to the min levels
+			h.swap(idx, parentIDX)
+			h.pushUpLevels(parentIDX, h.less)
+		} else {
+			h.pushUpLevels(idx, h.greater)
+		}
+	}
+}
+
+// Swap the node with its grandparent while before says it should be above it.
+func (h *MinMaxHeap[T]) pushUpLevels(idx int, before func(i, j int) bool) {
+	for idx > 2 {
+		grandparentIDX := ((idx-1)/2 - 1) / 2
+		if !before(idx, grandparentIDX) {
+			return
+		}
+		h.swap(idx, grandparentIDX)
+		idx = grandparentIDX
+	}
+}
+
+// Push the node at the given index down toward the bottom.
+// On a min level the node is swapped with its smallest descendant, on a max level with its largest,
+// looking at both the children and the grandchildren.
+func (h *MinMaxHeap[T]) pushDown(idx int) {
+	before := h.greater
+	if h.isMinLevel(idx) {
+		before = h.less
+	}
+
+	for {
+		firstChildIDX := 2*idx + 1
+		if firstChildIDX >= len(h.values) {
+			return
+		}
+
+		// Find the descendant that should be above all others
+		bestIDX := firstChildIDX
+		if firstChildIDX+1 < len(h.values) && before(firstChildIDX+1, bestIDX) {
+			bestIDX = firstChildIDX + 1
+		}
+		firstGrandchildIDX := 2*firstChildIDX + 1
+		for i := firstGrandchildIDX; i < min(firstGrandchildIDX+4, len(h.values)); i++ {
+			if before(i, bestIDX) {
+				bestIDX = i
+			}
+		}
+
+		if !before(bestIDX, idx) {
+			return
+		}
+		h.swap(bestIDX, idx)
+		if bestIDX < firstGrandchildIDX {
+			// A child is on the other kind of level, and has no descendant to check
+			return
+		}
+
+		// The node moved down two levels, it must still be on the right side of the parent in between
+		if parentIDX := (bestIDX - 1) / 2; before(parentIDX, bestIDX) {
+			h.swap(parentIDX, bestIDX)
+		}
+		idx = bestIDX
+	}
+}
+
+// Check if the node at the given index is on a min level, the root is on level 0.
+func (h *MinMaxHeap[T]) isMinLevel(idx int) bool {
+	return bits.Len(uint(idx+1))%2 == 1
+}
+
+// Check if the node at i should be closer to the min end than the node at j.
+func (h *MinMaxHeap[T]) less(i, j int) bool {
+	return h.cmp(h.values[i], h.values[j])
+}
+
+// Check if the node at i should be closer to the max end than the node at j.
+func (h *MinMaxHeap[T]) greater(i, j int) bool {
+	return h.cmp(h.values[j], h.values[i])
+}
+
+func (h *MinMaxHeap[T]) swap(i, j int) {
+	h.values[i], h.values[j] = h.values[j], h.values[i]
+}
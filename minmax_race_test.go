@@ -0,0 +1,71 @@
+package collection_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/trviph/collection"
+)
+
+func TestMinMaxHeapRace(t *testing.T) {
+	var wg sync.WaitGroup
+	heap := collection.MustNewMinMaxHeap[int](collection.LessThan)
+	functions := []func(){
+		// Push to the heap
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				heap.Push(rand.Int())
+			}
+		},
+
+		// Pop the min end of the heap
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = heap.PopMin()
+			}
+		},
+
+		// Pop the max end of the heap
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = heap.PopMax()
+			}
+		},
+
+		// PushPop on the heap
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = heap.PushPop(rand.Int())
+			}
+		},
+
+		// Peek at both ends of the heap
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = heap.Min()
+				_, _ = heap.Max()
+				_ = heap.Len()
+			}
+		},
+
+		// Check if heap is empty
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = heap.IsEmpty()
+			}
+		},
+	}
+
+	wg.Add(len(functions))
+	for _, f := range functions {
+		go f()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,180 @@
+package collection_test
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/trviph/collection"
+)
+
+func TestNewMinMaxHeap(t *testing.T) {
+	if _, err := collection.NewMinMaxHeap[int](nil); err == nil {
+		t.Errorf(testFailedMsg, "TestNewMinMaxHeap", "error", nil)
+	}
+	if _, err := collection.NewMinMaxHeap[int](collection.LessThan); err != nil {
+		t.Errorf(testFailedMsg, "TestNewMinMaxHeap", "nil error", err)
+	}
+}
+
+func TestMustNewMinMaxHeap(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewMinMaxHeap", "panic", r)
+		}
+	}()
+	_ = collection.MustNewMinMaxHeap[any](nil)
+}
+
+func TestMinMaxHeap(t *testing.T) {
+	if err := minHeapTest(collection.MustNewMinMaxHeap[int](collection.LessThan)); err != nil {
+		t.Errorf(testFailedMsg, "TestMinMaxHeap", "nil error", err)
+	}
+	if err := maxHeapTest(collection.MustNewMinMaxHeap[int](collection.GreaterThanOrEqual)); err != nil {
+		t.Errorf(testFailedMsg, "TestMinMaxHeap", "nil error", err)
+	}
+
+	cmps := map[string]func(int, int) bool{
+		"LessThan":           collection.LessThan[int],
+		"LessThanOrEqual":    collection.LessThanOrEqual[int],
+		"GreaterThan":        collection.GreaterThan[int],
+		"GreaterThanOrEqual": collection.GreaterThanOrEqual[int],
+	}
+	for name, cmp := range cmps {
+		heap := collection.MustNewMinMaxHeap(cmp)
+		// The values the heap should hold, from the min end to the max end
+		want := make([]int, 0)
+		sortWant := func() {
+			slices.SortStableFunc(want, func(a, b int) int {
+				if a == b {
+					return 0
+				} else if cmp(a, b) {
+					return -1
+				}
+				return 1
+			})
+		}
+
+		for i := 0; i < 5000; i++ {
+			switch op := rand.Intn(10); {
+			case op < 4:
+				// Push a few values, some of them equal
+				values := make([]int, randint(1, 3))
+				for j := range values {
+					values[j] = rand.Intn(100)
+				}
+				heap.Push(values...)
+				want = append(want, values...)
+				sortWant()
+			case op < 6:
+				got, err := heap.PopMin()
+				if len(want) == 0 {
+					if !errors.Is(err, collection.ErrIsEmpty) {
+						t.Fatalf(testFailedMsg, name, collection.ErrIsEmpty, err)
+					}
+					continue
+				}
+				if err != nil || got != want[0] {
+					t.Fatalf(testFailedMsg, name, want[0], got)
+				}
+				want = want[1:]
+			case op < 8:
+				got, err := heap.PopMax()
+				if len(want) == 0 {
+					if !errors.Is(err, collection.ErrIsEmpty) {
+						t.Fatalf(testFailedMsg, name, collection.ErrIsEmpty, err)
+					}
+					continue
+				}
+				if err != nil || got != want[len(want)-1] {
+					t.Fatalf(testFailedMsg, name, want[len(want)-1], got)
+				}
+				want = want[:len(want)-1]
+			default:
+				value := rand.Intn(100)
+				got, err := heap.PushPop(value)
+				if len(want) == 0 {
+					if !errors.Is(err, collection.ErrIsEmpty) {
+						t.Fatalf(testFailedMsg, name, collection.ErrIsEmpty, err)
+					}
+					continue
+				}
+				want = append(want, value)
+				sortWant()
+				if err != nil || got != want[0] {
+					t.Fatalf(testFailedMsg, name, want[0], got)
+				}
+				want = want[1:]
+			}
+
+			if got := heap.Len(); got != len(want) {
+				t.Fatalf(testFailedMsg, name, len(want), got)
+			}
+			if len(want) == 0 {
+				continue
+			}
+			if got, err := heap.Min(); err != nil || got != want[0] {
+				t.Fatalf(testFailedMsg, name, want[0], got)
+			}
+			if got, err := heap.Max(); err != nil || got != want[len(want)-1] {
+				t.Fatalf(testFailedMsg, name, want[len(want)-1], got)
+			}
+		}
+	}
+}
+
+func TestMinMaxHeapEmpty(t *testing.T) {
+	heap := collection.MustNewMinMaxHeap[int](collection.LessThan)
+	if !heap.IsEmpty() {
+		t.Errorf(testFailedMsg, "TestMinMaxHeapEmpty", true, false)
+	}
+	// Should return error since the heap is empty
+	if _, err := heap.Pop(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestMinMaxHeapEmpty", collection.ErrIsEmpty, err)
+	}
+	if _, err := heap.Top(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestMinMaxHeapEmpty", collection.ErrIsEmpty, err)
+	}
+	if _, err := heap.Min(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestMinMaxHeapEmpty", collection.ErrIsEmpty, err)
+	}
+	if _, err := heap.Max(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestMinMaxHeapEmpty", collection.ErrIsEmpty, err)
+	}
+
+	// A single value is both the min and the max
+	heap.Push(1)
+	if got, err := heap.Top(); err != nil || got != 1 {
+		t.Errorf(testFailedMsg, "TestMinMaxHeapEmpty", 1, got)
+	}
+	if got, err := heap.PopMax(); err != nil || got != 1 {
+		t.Errorf(testFailedMsg, "TestMinMaxHeapEmpty", 1, got)
+	}
+	if !heap.IsEmpty() {
+		t.Errorf(testFailedMsg, "TestMinMaxHeapEmpty", true, false)
+	}
+}
+
+func TestMinMaxHeapTopK(t *testing.T) {
+	// Keep the 10 largest values, evicting the smallest one when a larger value comes
+	const k = 10
+	heap := collection.MustNewMinMaxHeap[int](collection.LessThan)
+	values := rand.Perm(1000)
+	for _, value := range values {
+		if heap.Len() < k {
+			heap.Push(value)
+		} else if _, err := heap.PushPop(value); err != nil {
+			t.Errorf(testFailedMsg, "TestMinMaxHeapTopK", "nil error", err)
+		}
+	}
+
+	// The largest values come out of the max end first
+	for want := 999; want >= 1000-k; want-- {
+		if got, err := heap.PopMax(); err != nil {
+			t.Errorf(testFailedMsg, "TestMinMaxHeapTopK", "nil error", err)
+		} else if got != want {
+			t.Errorf(testFailedMsg, "TestMinMaxHeapTopK", want, got)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package collection
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/trviph/collection/internal"
+)
+
+// A [PairingHeap] implemented by using a tree of nodes where every node points to its leftmost child and its next sibling,
+// a heap that is simple and fast in practice, with O(1) Push and Meld, and O(log n) amortized Pop.
+// Decreasing a value takes sub-logarithmic amortized time, which is proven to be more than O(1),
+// see [FibonacciHeap] for O(1) amortized decrease of a value.
+// PairingHeap is thread-safe, because it only allow one goroutine at a time to access it data.
+//
+// See [NewHeap] for how the values are compared.
+type PairingHeap[T any] struct {
+	mu   sync.RWMutex
+	id   uint64
+	root *PairingHeapItem[T]
+	size int
+	cmp  func(T, T) bool
+	// The owner of the nodes pushed into this heap.
+	owner *heapOwner
+	// Reused by Pop to pair the children of the root.
+	pairs []*PairingHeapItem[T]
+}
+
+// A node of a [PairingHeap], which is also the handle returned by [PairingHeap.PushItem],
+// and can be given to [PairingHeap.Update] and [PairingHeap.Remove].
+// The handle is no longer valid once its value leaves the heap.
+type PairingHeapItem[T any] struct {
+	value T
+	// The leftmost child, and the next sibling to the right.
+	child, sibling *PairingHeapItem[T]
+	// The parent if this is the leftmost child, else the previous sibling to the left.
+	prev *PairingHeapItem[T]
+	// The owner of the heap the node was pushed into, it never changes.
+	owner  *heapOwner
+	inHeap bool
+}
+
+// A heapOwner tells which heap a node belongs to, without updating the nodes when a heap is melded into another.
+// When a heap is melded, the owners of the two heaps are linked, so the owner of a node is found
+// by following the links, like a union-find.
+type heapOwner struct {
+	next atomic.Pointer[heapOwner]
+	// An upper bound of the number of links to reach this owner, only meaningful while it is not linked.
+	rank int
+}
+
+// Link two owners that are not linked yet, the one with the lower rank is linked to the other,
+// so the links stay short. Returns the owner at the end of the links.
+func (o *heapOwner) union(other *heapOwner) *heapOwner {
+	if o.rank < other.rank {
+		o, other = other, o
+	}
+	other.next.Store(o)
+	if o.rank == other.rank {
+		o.rank++
+	}
+	return o
+}
+
+// Find the owner at the end of the links, shortening the links on the way.
+// The links are atomic since any heap may look up the owner of any node.
+func (o *heapOwner) find() *heapOwner {
+	root := o
+	for next := root.next.Load(); next != nil; next = root.next.Load() {
+		root = next
+	}
+	for o != root {
+		next := o.next.Load()
+		o.next.Store(root)
+		o = next
+	}
+	return root
+}
+
+// Gives every mergeable heap an id, so two heaps are always locked in the same order when melding.
+var heapIDs atomic.Uint64
+
+var _ internal.Heap[any] = (*PairingHeap[any])(nil)
+
+// [NewPairingHeap] creates a new [PairingHeap], see [NewHeap] for cmp.
+// This will return an error if cmp is nil, if you want to panic instead use [MustNewPairingHeap].
+//
+//	heap, err := collection.NewPairingHeap(collection.LessThan[int])
+func NewPairingHeap[T any](cmp func(current, other T) bool) (*PairingHeap[T], error) {
+	if cmp == nil {
+		return nil, fmt.Errorf("function argument is required to create a new pairing heap")
+	}
+
+	return &PairingHeap[T]{id: heapIDs.Add(1), cmp: cmp, owner: &heapOwner{}}, nil
+}
+
+// Like [NewPairingHeap] but will panic if cmp is nil.
+func MustNewPairingHeap[T any](cmp func(current, other T) bool) *PairingHeap[T] {
+	return Must(func() (*PairingHeap[T], error) {
+		return NewPairingHeap(cmp)
+	})
+}
+
+// Push values into the heap.
+func (h *PairingHeap[T]) Push(values ...T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, value := range values {
+		h.push(value)
+	}
+}
+
+// PushItem pushes a value into the heap like [PairingHeap.Push],
+// and returns a handle to it, to update or remove the value later.
+func (h *PairingHeap[T]) PushItem(value T) *PairingHeapItem[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.push(value)
+}
+
+func (h *PairingHeap[T]) push(value T) *PairingHeapItem[T] {
+	node := &PairingHeapItem[T]{value: value, owner: h.owner, inHeap: true}
+	h.root = h.link(h.root, node)
+	h.size++
+	return node
+}
+
+// Get a value at the root node, and remove it from the heap.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *PairingHeap[T]) Pop() (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.root == nil {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to pop on pairing heap, cause %w", ErrIsEmpty)
+	}
+	return h.pop(), nil
+}
+
+// Remove the root node, and return its value.
+func (h *PairingHeap[T]) pop() T {
+	node := h.root
+	h.root = h.mergePairs(node.child)
+	h.size--
+	h.detach(node)
+	return node.value
+}
+
+// Push a value into the heap and then pop the root node.
+// This function is equivalent to call a [PairingHeap.Push] followed by a [PairingHeap.Pop],
+// but does not allocate a node if the value would be the root.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *PairingHeap[T]) PushPop(value T) (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.root == nil {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to push and pop on pairing heap, cause %w", ErrIsEmpty)
+	}
+	if h.cmp(value, h.root.value) {
+		return value, nil
+	}
+	res := h.pop()
+	h.push(value)
+	return res, nil
+}
+
+// Peek at the value at the root node without removing it from the heap.
+// Returns [ErrIsEmpty] if the heap is empty.
+func (h *PairingHeap[T]) Top() (T, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.root == nil {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to peek at pairing heap, cause %w", ErrIsEmpty)
+	}
+	return h.root.value, nil
+}
+
+// IsEmpty returns true if the heap does not hold any value.
+func (h *PairingHeap[T]) IsEmpty() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.root == nil
+}
+
+// Len returns the number of values in the heap.
+func (h *PairingHeap[T]) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.size
+}
+
+// Update replaces the value of the handle, and moves it to its new place in the heap.
+// If the new value moves toward the root, this takes sub-logarithmic amortized time,
+// which grows at least as fast as log log n, instead of O(1) as in [FibonacciHeap], else O(log n) amortized.
+// Returns [ErrNotFound] if the value of the handle is no longer in the heap, or the handle belongs to another heap.
+func (h *PairingHeap[T]) Update(item *PairingHeapItem[T], value T) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.has(item) {
+		return fmt.Errorf("failed to update pairing heap, cause %w", ErrNotFound)
+	}
+
+	if h.cmp(value, item.value) {
+		// The subtree of the node is still in order, cut it and link it with the root
+		item.value = value
+		if item != h.root {
+			h.cut(item)
+			h.root = h.link(h.root, item)
+		}
+		return nil
+	}
+
+	// Else the node may have to go below its children, take it out and push it again
+	h.remove(item)
+	item.value = value
+	item.inHeap = true
+	h.root = h.link(h.root, item)
+	h.size++
+	return nil
+}
+
+// Remove removes the value of the handle from the heap, and returns it.
+// Returns [ErrNotFound] if the value of the handle is no longer in the heap, or the handle belongs to another heap.
+func (h *PairingHeap[T]) Remove(item *PairingHeapItem[T]) (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.has(item) {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("failed to remove from pairing heap, cause %w", ErrNotFound)
+	}
+	h.remove(item)
+	return item.value, nil
+}
+
+// Remove a node from anywhere in the heap.
+func (h *PairingHeap[T]) remove(node *PairingHeapItem[T]) {
+	if node == h.root {
+		h.pop()
+		return
+	}
+	h.cut(node)
+	h.root = h.link(h.root, h.mergePairs(node.child))
+	h.size--
+	h.detach(node)
+}
+
+// Meld moves all the values of other into the heap in O(1), leaving other empty.
+// The handles of the values of other now belong to the heap.
+// Both heaps should compare values the same way.
+func (h *PairingHeap[T]) Meld(other *PairingHeap[T]) {
+	if other == nil || other == h {
+		return
+	}
+	first, second := h, other
+	if first.id > second.id {
+		first, second = second, first
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	h.root = h.link(h.root, other.root)
+	h.size += other.size
+	h.owner = h.owner.union(other.owner)
+	other.owner = &heapOwner{}
+	other.root = nil
+	other.size = 0
+}
+
+// Check if the node of the handle is in this heap.
+func (h *PairingHeap[T]) has(item *PairingHeapItem[T]) bool {
+	return item != nil && item.owner.find() == h.owner && item.inHeap
+}
+
+// Link two trees, the root that satisfies cmp becomes the parent, and returns it.
+func (h *PairingHeap[T]) link(a, b *PairingHeapItem[T]) *PairingHeapItem[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if !h.cmp(a.value, b.value) {
+		a, b = b, a
+	}
+	// Make b the leftmost child of a
+	b.prev = a
+	b.sibling = a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+	return a
+}
+
+// Cut the subtree of a node that is not the root out of the tree.
+func (h *PairingHeap[T]) cut(node *PairingHeapItem[T]) {
+	if node.prev.child == node {
+		node.prev.child = node.sibling
+	} else {
+		node.prev.sibling = node.sibling
+	}
+	if node.sibling != nil {
+		node.sibling.prev = node.prev
+	}
+	node.prev = nil
+	node.sibling = nil
+}
+
+// Merge a list of siblings into one tree and return its root, by linking them in pairs from left to right,
+// then linking the pairs from right to left.
+func (h *PairingHeap[T]) mergePairs(first *PairingHeapItem[T]) *PairingHeapItem[T] {
+	pairs := h.pairs[:0]
+	for first != nil {
+		a, b := first, first.sibling
+		first = nil
+		if b != nil {
+			first = b.sibling
+		}
+		a.prev, a.sibling = nil, nil
+		if b != nil {
+			b.prev, b.sibling = nil, nil
+		}
+		pairs = append(pairs, h.link(a, b))
+	}
+
+	var root *PairingHeapItem[T]
+	for i := len(pairs) - 1; i >= 0; i-- {
+		root = h.link(pairs[i], root)
+		pairs[i] = nil
+	}
+	h.pairs = pairs
+	return root
+}
+
+// Unlink a node that left the heap, so it does not keep the other nodes alive.
+func (h *PairingHeap[T]) detach(node *PairingHeapItem[T]) {
+	node.child, node.sibling, node.prev = nil, nil, nil
+	node.inHeap = false
+}
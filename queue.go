@@ -1,47 +1,314 @@
 package collection
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/trviph/collection/internal"
 )
 
+// OverflowPolicy decides what a bounded [Queue] does with a value pushed while it is full.
+type OverflowPolicy int
+
+const (
+	// Wait until a value is dequeued to make room for the new value, see PushWait.
+	OverflowBlock OverflowPolicy = iota
+	// Reject the new value, TryPush and PushWait return [ErrFull] as an error.
+	OverflowReject
+	// Drop the value at the front of the queue to make room for the new value.
+	OverflowDropOldest
+	// Drop the new value.
+	OverflowDropNewest
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowReject:
+		return "reject"
+	case OverflowDropOldest:
+		return "drop oldest"
+	case OverflowDropNewest:
+		return "drop newest"
+	default:
+		return "unknown"
+	}
+}
+
 // A first-in-first-out [Queue] implemented by using [List] as the base.
 // Since [List] is thread-safe, [Queue] should also be thread-safe.
 type Queue[T any] struct {
 	mu   sync.Mutex
 	list *List[T]
+
+	// The maximum number of values in the queue, zero means the queue is unbounded.
+	cap    int
+	policy OverflowPolicy
+
+	// Calls of DequeueWait waiting for a value, the one waiting the longest first.
+	// A waiter is handed a value through its channel, which is closed if the queue is closed.
+	waiters []chan T
+	// Calls of Push and PushWait waiting for room in a full queue, the one waiting the longest first.
+	producers []*producer[T]
+	closed    bool
+}
+
+// A value waiting for room in a full queue.
+type producer[T any] struct {
+	value T
+	// Closed once the value is pushed, or the queue is closed in which case err is set.
+	done chan struct{}
+	err  error
 }
 
 // Interface guard
-var _ internal.Queue[any] = (*Queue[any])(nil)
+var (
+	_ internal.Queue[any]         = (*Queue[any])(nil)
+	_ internal.BlockingQueue[any] = (*Queue[any])(nil)
+	_ internal.BoundedQueue[any]  = (*Queue[any])(nil)
+)
 
-// [NewQueue] creates a new [Queue] of type T.
+// [NewQueue] creates a new [Queue] of type T, which is unbounded.
 func NewQueue[T any](values ...T) *Queue[T] {
 	return &Queue[T]{list: NewList(values...)}
 }
 
+// [NewBoundedQueue] creates a new [Queue] of type T, which holds at most capacity values.
+// The policy decides what happens to a value pushed while the queue is full.
+// This will return an error if capacity is less than 1 or the policy is unknown,
+// if you want to panic instead use [MustNewBoundedQueue].
+//
+//	queue, err := collection.NewBoundedQueue[int](1024, collection.OverflowBlock)
+func NewBoundedQueue[T any](capacity int, policy OverflowPolicy) (*Queue[T], error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("failed to create bounded queue; cause by invalid specified capacity of %d", capacity)
+	}
+	if policy < OverflowBlock || policy > OverflowDropNewest {
+		return nil, fmt.Errorf("failed to create bounded queue; cause by unknown overflow policy of %d", policy)
+	}
+	return &Queue[T]{list: NewList[T](), cap: capacity, policy: policy}, nil
+}
+
+// Like [NewBoundedQueue] but will panic on error.
+func MustNewBoundedQueue[T any](capacity int, policy OverflowPolicy) *Queue[T] {
+	return Must(func() (*Queue[T], error) {
+		return NewBoundedQueue[T](capacity, policy)
+	})
+}
+
 // Length returns the number of values current in the queue.
 func (q *Queue[T]) Length() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	return q.list.Length()
 }
 
+// Cap returns the maximum number of values in the queue, zero means the queue is unbounded.
+func (q *Queue[T]) Cap() int {
+	return q.cap
+}
+
 // Push a list of values in to the queue, starting from left to right.
+// Values are handed to the calls of DequeueWait waiting for them first, the one waiting the longest first.
+// Values pushed after the queue is closed are dropped.
+//
+// If the queue is bounded and full, the overflow policy decides what happens to the remaining values:
+// with [OverflowBlock] Push waits for room like PushWait without a deadline,
+// with [OverflowReject] or [OverflowDropNewest] they are dropped,
+// with [OverflowDropOldest] the values at the front of the queue are dropped to make room for them.
+// Use TryPush or PushWait to know if the values are pushed.
 func (q *Queue[T]) Push(values ...T) {
-	q.list.Append(values...)
+	_ = q.push(context.Background(), values, true)
+}
+
+// TryPush is like Push, but never waits for room.
+// If the queue is full and its overflow policy is [OverflowBlock] or [OverflowReject],
+// the values before the first value that does not fit are pushed, and it returns [ErrFull] as an error.
+// It returns [ErrClosed] as an error if the queue is closed.
+func (q *Queue[T]) TryPush(values ...T) error {
+	return q.push(context.Background(), values, false)
+}
+
+// PushWait is like Push, but if the queue is full and its overflow policy is [OverflowBlock],
+// it waits until there is room for each value, the queue is closed, or the context is done.
+// Calls waiting at the same time push their values in the order they started waiting.
+// For the other policies it is the same as TryPush.
+//
+// If the queue is closed or the context is done while waiting, the values before the waiting one stay pushed,
+// and it returns [ErrClosed] or the error of the context.
+func (q *Queue[T]) PushWait(ctx context.Context, values ...T) error {
+	return q.push(ctx, values, true)
+}
+
+// Push the values one by one, applying the overflow policy when the queue is full.
+// Only [OverflowBlock] waits, and only if wait is true.
+func (q *Queue[T]) push(ctx context.Context, values []T, wait bool) error {
+	q.mu.Lock()
+	for _, value := range values {
+		if q.closed {
+			q.mu.Unlock()
+			return fmt.Errorf("failed to push to queue, cause by %w", ErrClosed)
+		}
+		if q.add(value) {
+			continue
+		}
+
+		switch {
+		case q.policy == OverflowDropOldest:
+			_, _ = q.list.Dequeue()
+			q.list.Append(value)
+		case q.policy == OverflowDropNewest:
+			// The value is dropped
+		case q.policy == OverflowReject || !wait:
+			q.mu.Unlock()
+			return fmt.Errorf("failed to push to queue, cause by %w", ErrFull)
+		default:
+			p := &producer[T]{value: value, done: make(chan struct{})}
+			q.producers = append(q.producers, p)
+			q.mu.Unlock()
+			if err := q.waitRoom(ctx, p); err != nil {
+				return fmt.Errorf("failed to push to queue, cause by %w", err)
+			}
+			q.mu.Lock()
+		}
+	}
+	q.mu.Unlock()
+	return nil
+}
+
+// Wait until the value of a producer is pushed, the queue is closed or the context is done.
+func (q *Queue[T]) waitRoom(ctx context.Context, p *producer[T]) error {
+	select {
+	case <-p.done:
+		return p.err
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		if i := slices.Index(q.producers, p); i >= 0 {
+			q.producers = slices.Delete(q.producers, i, i+1)
+			return ctx.Err()
+		}
+		// The value was pushed or the queue was closed meanwhile
+		return p.err
+	}
+}
+
+// Add a value to the queue while holding the mutex, handing it to the longest waiting call of DequeueWait if there is any.
+// It returns false if the queue is full.
+func (q *Queue[T]) add(value T) bool {
+	if len(q.waiters) > 0 {
+		// The channel is buffered, so this does not block
+		q.waiters[0] <- value
+		q.waiters[0] = nil
+		q.waiters = q.waiters[1:]
+		return true
+	}
+	if q.cap > 0 && q.list.Length() >= q.cap {
+		return false
+	}
+	q.list.Append(value)
+	return true
+}
+
+// Move the values of waiting producers into the queue while there is room, this is called after dequeuing.
+func (q *Queue[T]) admit() {
+	for len(q.producers) > 0 && q.list.Length() < q.cap {
+		p := q.producers[0]
+		q.producers[0] = nil
+		q.producers = q.producers[1:]
+		q.list.Append(p.value)
+		close(p.done)
+	}
 }
 
 // Dequeue get the value from the front of the queue, and remove it from the queue.
-// If the queue is empty return [ErrIsEmpty] as an error.
+// If the queue is empty return [ErrIsEmpty] as an error, or [ErrClosed] if the queue is also closed.
 func (q *Queue[T]) Dequeue() (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	if value, err := q.list.Dequeue(); err != nil {
+		if q.closed {
+			err = ErrClosed
+		}
 		return value, fmt.Errorf("failed to dequeue queue, cause by %w", err)
 	} else {
+		q.admit()
 		return value, nil
 	}
 }
 
+// DequeueWait is like Dequeue, but if the queue is empty it waits until a value is pushed,
+// the queue is closed, or the context is done.
+// Calls waiting at the same time get the pushed values in the order they started waiting.
+//
+// Once the queue is closed, the values left in it can still be dequeued,
+// then DequeueWait returns [ErrClosed] as an error.
+// If the context is done before a value is pushed, it returns the error of the context.
+func (q *Queue[T]) DequeueWait(ctx context.Context) (T, error) {
+	var zeroValue T
+	q.mu.Lock()
+	if value, err := q.list.Dequeue(); err == nil {
+		q.admit()
+		q.mu.Unlock()
+		return value, nil
+	}
+	if q.closed {
+		q.mu.Unlock()
+		return zeroValue, fmt.Errorf("failed to dequeue queue, cause by %w", ErrClosed)
+	}
+	waiter := make(chan T, 1)
+	q.waiters = append(q.waiters, waiter)
+	q.mu.Unlock()
+
+	select {
+	case value, ok := <-waiter:
+		if !ok {
+			return zeroValue, fmt.Errorf("failed to dequeue queue, cause by %w", ErrClosed)
+		}
+		return value, nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		if i := slices.Index(q.waiters, waiter); i >= 0 {
+			q.waiters = slices.Delete(q.waiters, i, i+1)
+			return zeroValue, fmt.Errorf("failed to dequeue queue, cause by %w", ctx.Err())
+		}
+		// A value was handed over or the queue was closed meanwhile, do not lose the value
+		if value, ok := <-waiter; ok {
+			return value, nil
+		}
+		return zeroValue, fmt.Errorf("failed to dequeue queue, cause by %w", ErrClosed)
+	}
+}
+
+// Close closes the queue, values can no longer be pushed but the values left in it can still be dequeued.
+// The calls of DequeueWait waiting for a value, and the calls of PushWait waiting for room,
+// return [ErrClosed] as an error. The values waiting for room are dropped.
+// Close always returns a nil error, it is safe to call more than once.
+func (q *Queue[T]) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	for _, waiter := range q.waiters {
+		close(waiter)
+	}
+	q.waiters = nil
+	for _, p := range q.producers {
+		p.err = ErrClosed
+		close(p.done)
+	}
+	q.producers = nil
+	return nil
+}
+
 // Front get the value from the front but does not remove it from the queue.
 // If the queue is empty return [ErrIsEmpty] as an error.
 func (q *Queue[T]) Front() (T, error) {
@@ -1,9 +1,11 @@
 package collection_test
 
 import (
+	"context"
 	"math/rand"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/trviph/collection"
 )
@@ -51,3 +53,124 @@ func TestQueueRace(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestQueueWaitRace(t *testing.T) {
+	var wg sync.WaitGroup
+	queue := collection.NewQueue[int]()
+	pushes := randint(10, 1000)
+	functions := []func(){
+		// Push to the queue
+		func() {
+			defer wg.Done()
+			for i := 0; i < pushes; i++ {
+				queue.Push(rand.Int())
+			}
+		},
+
+		// Dequeue from the queue until it is closed
+		func() {
+			defer wg.Done()
+			for {
+				if _, err := queue.DequeueWait(context.Background()); err != nil {
+					return
+				}
+			}
+		},
+
+		// Dequeue from the queue until it is closed
+		func() {
+			defer wg.Done()
+			for {
+				if _, err := queue.DequeueWait(context.Background()); err != nil {
+					return
+				}
+			}
+		},
+
+		// Dequeue with a context that is done soon
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 100); i++ {
+				ctx, cancel := context.WithCancel(context.Background())
+				go cancel()
+				_, _ = queue.DequeueWait(ctx)
+			}
+		},
+
+		// Close the queue
+		func() {
+			defer wg.Done()
+			time.Sleep(time.Duration(randint(1, 10)) * time.Millisecond)
+			_ = queue.Close()
+		},
+	}
+
+	wg.Add(len(functions))
+	for _, f := range functions {
+		go f()
+	}
+	wg.Wait()
+}
+
+func TestBoundedQueueRace(t *testing.T) {
+	policies := []collection.OverflowPolicy{
+		collection.OverflowBlock,
+		collection.OverflowReject,
+		collection.OverflowDropOldest,
+		collection.OverflowDropNewest,
+	}
+	for _, policy := range policies {
+		var wg sync.WaitGroup
+		queue := collection.MustNewBoundedQueue[int](randint(1, 10), policy)
+		functions := []func(){
+			// Push to the queue
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_ = queue.PushWait(context.Background(), rand.Int())
+				}
+			},
+
+			// Try to push to the queue
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					_ = queue.TryPush(rand.Int(), rand.Int())
+				}
+			},
+
+			// Dequeue from the queue until it is closed
+			func() {
+				defer wg.Done()
+				for {
+					if _, err := queue.DequeueWait(context.Background()); err != nil {
+						return
+					}
+				}
+			},
+
+			// Check the length of the queue
+			func() {
+				defer wg.Done()
+				for i := 0; i < randint(10, 1000); i++ {
+					if length := queue.Length(); length > queue.Cap() {
+						t.Errorf(testFailedMsg, "TestBoundedQueueRace", queue.Cap(), length)
+					}
+				}
+			},
+
+			// Close the queue
+			func() {
+				defer wg.Done()
+				time.Sleep(time.Duration(randint(1, 10)) * time.Millisecond)
+				_ = queue.Close()
+			},
+		}
+
+		wg.Add(len(functions))
+		for _, f := range functions {
+			go f()
+		}
+		wg.Wait()
+	}
+}
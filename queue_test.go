@@ -1,8 +1,10 @@
 package collection_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/trviph/collection"
 )
@@ -157,3 +159,288 @@ func TestQueueRear(t *testing.T) {
 		t.Errorf(testFailedMsg, "TestQueueRear", 5, value)
 	}
 }
+
+func TestQueueDequeueWait(t *testing.T) {
+	queue := collection.NewQueue(1)
+
+	// Should got 1 right away
+	value, err := queue.DequeueWait(context.Background())
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestQueueDequeueWait", "nil error", err)
+	}
+	if value != 1 {
+		t.Errorf(testFailedMsg, "TestQueueDequeueWait", 1, value)
+	}
+
+	// Should wait until 2 is pushed
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.Push(2)
+	}()
+	value, err = queue.DequeueWait(context.Background())
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestQueueDequeueWait", "nil error", err)
+	}
+	if value != 2 {
+		t.Errorf(testFailedMsg, "TestQueueDequeueWait", 2, value)
+	}
+	if queue.Length() != 0 {
+		t.Errorf(testFailedMsg, "TestQueueDequeueWait", 0, queue.Length())
+	}
+
+	// Should stop waiting when the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = queue.DequeueWait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf(testFailedMsg, "TestQueueDequeueWait", context.DeadlineExceeded, err)
+	}
+
+	// The cancelled call no longer waits for values
+	queue.Push(3)
+	if queue.Length() != 1 {
+		t.Errorf(testFailedMsg, "TestQueueDequeueWait", 1, queue.Length())
+	}
+}
+
+func TestQueueDequeueWaitFair(t *testing.T) {
+	queue := collection.NewQueue[int]()
+	waiters := 5
+	results := make([]chan int, waiters)
+	for i := range waiters {
+		results[i] = make(chan int, 1)
+		go func() {
+			value, _ := queue.DequeueWait(context.Background())
+			results[i] <- value
+		}()
+		// Let the call start waiting before the next one
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Should be handed to the one waiting the longest first
+	queue.Push(0, 1, 2)
+	queue.Push(3, 4)
+	for i := range waiters {
+		if value := <-results[i]; value != i {
+			t.Errorf(testFailedMsg, "TestQueueDequeueWaitFair", i, value)
+		}
+	}
+}
+
+func TestQueueClose(t *testing.T) {
+	queue := collection.NewQueue(1, 2)
+	if err := queue.Close(); err != nil {
+		t.Errorf(testFailedMsg, "TestQueueClose", "nil error", err)
+	}
+	if err := queue.Close(); err != nil {
+		t.Errorf(testFailedMsg, "TestQueueClose", "nil error", err)
+	}
+
+	// Should drop values pushed after closing
+	queue.Push(3)
+	if queue.Length() != 2 {
+		t.Errorf(testFailedMsg, "TestQueueClose", 2, queue.Length())
+	}
+
+	// Should drain the values left
+	value, err := queue.DequeueWait(context.Background())
+	if err != nil || value != 1 {
+		t.Errorf(testFailedMsg, "TestQueueClose", 1, value)
+	}
+	value, err = queue.Dequeue()
+	if err != nil || value != 2 {
+		t.Errorf(testFailedMsg, "TestQueueClose", 2, value)
+	}
+	if _, err := queue.DequeueWait(context.Background()); !errors.Is(err, collection.ErrClosed) {
+		t.Errorf(testFailedMsg, "TestQueueClose", collection.ErrClosed, err)
+	}
+	if _, err := queue.Dequeue(); !errors.Is(err, collection.ErrClosed) {
+		t.Errorf(testFailedMsg, "TestQueueClose", collection.ErrClosed, err)
+	}
+}
+
+func TestQueueCloseWakeWaiters(t *testing.T) {
+	queue := collection.NewQueue[int]()
+	errs := make(chan error, 3)
+	for range 3 {
+		go func() {
+			_, err := queue.DequeueWait(context.Background())
+			errs <- err
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// Should wake up every waiting call
+	_ = queue.Close()
+	for range 3 {
+		if err := <-errs; !errors.Is(err, collection.ErrClosed) {
+			t.Errorf(testFailedMsg, "TestQueueCloseWakeWaiters", collection.ErrClosed, err)
+		}
+	}
+}
+
+func TestNewBoundedQueue(t *testing.T) {
+	if _, err := collection.NewBoundedQueue[int](0, collection.OverflowBlock); err == nil {
+		t.Errorf(testFailedMsg, "TestNewBoundedQueue", "error", err)
+	}
+	if _, err := collection.NewBoundedQueue[int](1, collection.OverflowPolicy(-1)); err == nil {
+		t.Errorf(testFailedMsg, "TestNewBoundedQueue", "error", err)
+	}
+	if _, err := collection.NewBoundedQueue[int](1, collection.OverflowDropNewest+1); err == nil {
+		t.Errorf(testFailedMsg, "TestNewBoundedQueue", "error", err)
+	}
+
+	queue := collection.MustNewBoundedQueue[int](3, collection.OverflowReject)
+	if queue.Cap() != 3 {
+		t.Errorf(testFailedMsg, "TestNewBoundedQueue", 3, queue.Cap())
+	}
+	// An unbounded queue has no capacity
+	if unbounded := collection.NewQueue(1, 2); unbounded.Cap() != 0 {
+		t.Errorf(testFailedMsg, "TestNewBoundedQueue", 0, unbounded.Cap())
+	}
+	if collection.OverflowDropOldest.String() != "drop oldest" {
+		t.Errorf(testFailedMsg, "TestNewBoundedQueue", "drop oldest", collection.OverflowDropOldest.String())
+	}
+}
+
+func TestBoundedQueueBlock(t *testing.T) {
+	queue := collection.MustNewBoundedQueue[int](2, collection.OverflowBlock)
+	queue.Push(1, 2)
+
+	if err := queue.TryPush(3); !errors.Is(err, collection.ErrFull) {
+		t.Errorf(testFailedMsg, "TestBoundedQueueBlock", collection.ErrFull, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := queue.PushWait(ctx, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf(testFailedMsg, "TestBoundedQueueBlock", context.DeadlineExceeded, err)
+	}
+
+	// Should wait until there is room for 3
+	errs := make(chan error, 1)
+	go func() {
+		errs <- queue.PushWait(context.Background(), 3)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if queue.Length() != 2 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueBlock", 2, queue.Length())
+	}
+	if value, err := queue.Dequeue(); err != nil || value != 1 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueBlock", 1, value)
+	}
+	if err := <-errs; err != nil {
+		t.Errorf(testFailedMsg, "TestBoundedQueueBlock", "nil error", err)
+	}
+	if value, err := queue.Rear(); err != nil || value != 3 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueBlock", 3, value)
+	}
+	if queue.Length() != 2 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueBlock", 2, queue.Length())
+	}
+}
+
+func TestBoundedQueueBlockFair(t *testing.T) {
+	queue := collection.MustNewBoundedQueue[int](1, collection.OverflowBlock)
+	queue.Push(0)
+	producers := 3
+	for i := 1; i <= producers; i++ {
+		go queue.Push(i)
+		// Let the call start waiting before the next one
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Should push the values in the order the calls started waiting
+	for i := 0; i <= producers; i++ {
+		if value, err := queue.DequeueWait(context.Background()); err != nil || value != i {
+			t.Errorf(testFailedMsg, "TestBoundedQueueBlockFair", i, value)
+		}
+	}
+}
+
+func TestBoundedQueueReject(t *testing.T) {
+	queue := collection.MustNewBoundedQueue[int](2, collection.OverflowReject)
+
+	// Should drop 3 since the queue is full
+	queue.Push(1, 2, 3)
+	if queue.Length() != 2 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueReject", 2, queue.Length())
+	}
+	if value, err := queue.Rear(); err != nil || value != 2 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueReject", 2, value)
+	}
+	if err := queue.TryPush(3); !errors.Is(err, collection.ErrFull) {
+		t.Errorf(testFailedMsg, "TestBoundedQueueReject", collection.ErrFull, err)
+	}
+	if err := queue.PushWait(context.Background(), 3); !errors.Is(err, collection.ErrFull) {
+		t.Errorf(testFailedMsg, "TestBoundedQueueReject", collection.ErrFull, err)
+	}
+
+	// Should push the values before the one that does not fit
+	_, _ = queue.Dequeue()
+	if err := queue.TryPush(3, 4); !errors.Is(err, collection.ErrFull) {
+		t.Errorf(testFailedMsg, "TestBoundedQueueReject", collection.ErrFull, err)
+	}
+	if value, err := queue.Rear(); err != nil || value != 3 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueReject", 3, value)
+	}
+}
+
+func TestBoundedQueueDropOldest(t *testing.T) {
+	queue := collection.MustNewBoundedQueue[int](2, collection.OverflowDropOldest)
+
+	// Should drop 1 to make room for 3
+	queue.Push(1, 2, 3)
+	if value, err := queue.Front(); err != nil || value != 2 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueDropOldest", 2, value)
+	}
+	if err := queue.TryPush(4); err != nil {
+		t.Errorf(testFailedMsg, "TestBoundedQueueDropOldest", "nil error", err)
+	}
+	if value, err := queue.Front(); err != nil || value != 3 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueDropOldest", 3, value)
+	}
+	if value, err := queue.Rear(); err != nil || value != 4 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueDropOldest", 4, value)
+	}
+	if queue.Length() != 2 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueDropOldest", 2, queue.Length())
+	}
+}
+
+func TestBoundedQueueDropNewest(t *testing.T) {
+	queue := collection.MustNewBoundedQueue[int](2, collection.OverflowDropNewest)
+
+	// Should drop 3 and 4
+	queue.Push(1, 2, 3)
+	if err := queue.PushWait(context.Background(), 4); err != nil {
+		t.Errorf(testFailedMsg, "TestBoundedQueueDropNewest", "nil error", err)
+	}
+	if value, err := queue.Front(); err != nil || value != 1 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueDropNewest", 1, value)
+	}
+	if value, err := queue.Rear(); err != nil || value != 2 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueDropNewest", 2, value)
+	}
+}
+
+func TestBoundedQueueClose(t *testing.T) {
+	queue := collection.MustNewBoundedQueue[int](1, collection.OverflowBlock)
+	queue.Push(1)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- queue.PushWait(context.Background(), 2)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// Should wake up the waiting call and drop its value
+	_ = queue.Close()
+	if err := <-errs; !errors.Is(err, collection.ErrClosed) {
+		t.Errorf(testFailedMsg, "TestBoundedQueueClose", collection.ErrClosed, err)
+	}
+	if err := queue.TryPush(3); !errors.Is(err, collection.ErrClosed) {
+		t.Errorf(testFailedMsg, "TestBoundedQueueClose", collection.ErrClosed, err)
+	}
+	if queue.Length() != 1 {
+		t.Errorf(testFailedMsg, "TestBoundedQueueClose", 1, queue.Length())
+	}
+}
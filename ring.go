@@ -0,0 +1,203 @@
+package collection
+
+import (
+	"fmt"
+	"math/bits"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/trviph/collection/internal"
+)
+
+// A first-in-first-out [RingQueue] implemented by using a fixed-size array as a ring buffer,
+// which is safe to be used by many producers and many consumers at the same time without locks,
+// based on the [Bounded MPMC queue] of Dmitry Vyukov.
+//
+// Every cell of the ring has a sequence number telling whether it is ready to be written or read
+// at a given position, so producers and consumers only compete on the position they claim
+// with a compare-and-swap. Values are kept behind atomic pointers, so Front and Rear can copy them
+// without holding back producers and consumers, at the cost of one allocation per pushed value.
+//
+// Since the ring has a fixed size, Push waits for room when the ring is full, use TryPush to not wait.
+//
+// [Bounded MPMC queue]: https://www.1024cores.net/home/lock-free-algorithms/queues/bounded-mpmc-queue
+type RingQueue[T any] struct {
+	cells []ringCell[T]
+	mask  uint64
+
+	// The position of the next value to dequeue and the next value to push,
+	// they are padded to be on their own cache lines so producers and consumers do not slow each other down.
+	_    [56]byte
+	head atomic.Uint64
+	_    [56]byte
+	tail atomic.Uint64
+	_    [56]byte
+}
+
+type ringCell[T any] struct {
+	// Equal to the position of the cell when it is ready to be written,
+	// and to the position plus one when it holds the value of that position.
+	seq atomic.Uint64
+	// The value of the position, it is never written once pushed, so it can be read while being dequeued.
+	value atomic.Pointer[T]
+}
+
+// Interface guard
+var _ internal.Queue[any] = (*RingQueue[any])(nil)
+
+// [NewRingQueue] creates a new [RingQueue] of type T, which holds at most capacity values.
+// The capacity is rounded up to the next power of two.
+// This will return an error if capacity is less than 1, if you want to panic instead use [MustNewRingQueue].
+func NewRingQueue[T any](capacity int) (*RingQueue[T], error) {
+	if capacity < 1 || capacity > 1<<62 {
+		return nil, fmt.Errorf("failed to create ring queue; cause by invalid specified capacity of %d", capacity)
+	}
+
+	size := uint64(1) << bits.Len64(uint64(capacity-1))
+	q := &RingQueue[T]{cells: make([]ringCell[T], size), mask: size - 1}
+	for i := range q.cells {
+		q.cells[i].seq.Store(uint64(i))
+	}
+	return q, nil
+}
+
+// Like [NewRingQueue] but will panic on error.
+func MustNewRingQueue[T any](capacity int) *RingQueue[T] {
+	return Must(func() (*RingQueue[T], error) {
+		return NewRingQueue[T](capacity)
+	})
+}
+
+// Length returns the number of values current in the queue.
+// The result may be outdated by the time it is returned if the queue is in use.
+func (q *RingQueue[T]) Length() int {
+	// The head is loaded first, as it never goes past the tail
+	head := q.head.Load()
+	tail := q.tail.Load()
+	return int(min(tail-head, uint64(len(q.cells))))
+}
+
+// Cap returns the maximum number of values in the queue.
+func (q *RingQueue[T]) Cap() int {
+	return len(q.cells)
+}
+
+// Push a list of values in to the queue, starting from left to right.
+// If the queue is full, it waits for values to be dequeued by yielding the processor to other goroutines.
+// Values of different calls at the same time may be interleaved.
+func (q *RingQueue[T]) Push(values ...T) {
+	for _, value := range values {
+		for !q.push(value) {
+			runtime.Gosched()
+		}
+	}
+}
+
+// TryPush is like Push, but never waits for room.
+// If the queue is full, the values before the first value that does not fit are pushed,
+// and it returns [ErrFull] as an error.
+func (q *RingQueue[T]) TryPush(values ...T) error {
+	for _, value := range values {
+		if !q.push(value) {
+			return fmt.Errorf("failed to push to ring queue, cause by %w", ErrFull)
+		}
+	}
+	return nil
+}
+
+// Push a value, it returns false if the queue is full.
+func (q *RingQueue[T]) push(value T) bool {
+	for {
+		pos := q.tail.Load()
+		cell := &q.cells[pos&q.mask]
+		seq := cell.seq.Load()
+		switch {
+		case seq == pos:
+			// The cell is ready to be written, claim the position
+			if q.tail.CompareAndSwap(pos, pos+1) {
+				cell.value.Store(&value)
+				cell.seq.Store(pos + 1)
+				return true
+			}
+		case int64(seq-pos) < 0:
+			// The cell still holds the value of the previous lap
+			return false
+		}
+		// Else another producer claimed the position first, try the next one
+	}
+}
+
+// Dequeue get the value from the front of the queue, and remove it from the queue.
+// If the queue is empty return [ErrIsEmpty] as an error.
+func (q *RingQueue[T]) Dequeue() (T, error) {
+	var zeroValue T
+	for {
+		pos := q.head.Load()
+		cell := &q.cells[pos&q.mask]
+		seq := cell.seq.Load()
+		switch {
+		case seq == pos+1:
+			// The cell holds the value of the position, claim it
+			if q.head.CompareAndSwap(pos, pos+1) {
+				value := cell.value.Swap(nil)
+				cell.seq.Store(pos + q.mask + 1)
+				return *value, nil
+			}
+		case int64(seq-(pos+1)) < 0:
+			// The value of the position is not pushed yet
+			return zeroValue, fmt.Errorf("failed to dequeue ring queue, cause by %w", ErrIsEmpty)
+		}
+		// Else another consumer claimed the position first, try the next one
+	}
+}
+
+// Front get the value from the front but does not remove it from the queue.
+// If the queue is empty return [ErrIsEmpty] as an error.
+func (q *RingQueue[T]) Front() (T, error) {
+	for {
+		// The head is loaded first, so if the tail is the same the queue was empty when the tail is loaded
+		head := q.head.Load()
+		if q.tail.Load() == head {
+			var zeroValue T
+			return zeroValue, fmt.Errorf("failed to peek at the front of the ring queue, cause by %w", ErrIsEmpty)
+		}
+		if value, ok := q.peek(head, func() bool { return q.head.Load() == head }); ok {
+			return value, nil
+		}
+	}
+}
+
+// Rear get the value from the rear/end but does not remove it from the queue.
+// If the queue is empty return [ErrIsEmpty] as an error.
+func (q *RingQueue[T]) Rear() (T, error) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		if tail == head {
+			var zeroValue T
+			return zeroValue, fmt.Errorf("failed to peek at the rear of the ring queue, cause by %w", ErrIsEmpty)
+		}
+		pos := tail - 1
+		if value, ok := q.peek(pos, func() bool { return q.head.Load() <= pos }); ok {
+			return value, nil
+		}
+	}
+}
+
+// Copy the value of a position without dequeuing it, and without stopping it from being dequeued.
+// The value is loaded once the cell holds it, then inQueue tells if the position is still in the queue,
+// in which case the cell cannot have been dequeued or reused by a later lap since the value was loaded.
+// It returns false if the value cannot be copied, because it is not written yet or it was dequeued meanwhile.
+func (q *RingQueue[T]) peek(pos uint64, inQueue func() bool) (T, bool) {
+	var zeroValue T
+	cell := &q.cells[pos&q.mask]
+	if cell.seq.Load() != pos+1 {
+		runtime.Gosched()
+		return zeroValue, false
+	}
+	value := cell.value.Load()
+	if value == nil || !inQueue() {
+		return zeroValue, false
+	}
+	return *value, true
+}
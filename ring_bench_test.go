@@ -0,0 +1,46 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/trviph/collection"
+	"github.com/trviph/collection/internal"
+)
+
+const benchQueueCap = 1 << 10
+
+// Push then dequeue a value on the queue from one goroutine.
+func benchmarkQueue(b *testing.B, queue internal.Queue[int]) {
+	for i := 0; i < b.N; i++ {
+		queue.Push(i)
+		_, _ = queue.Dequeue()
+	}
+}
+
+// Push then dequeue a value on the queue from parallel goroutines.
+func benchmarkQueueParallel(b *testing.B, queue internal.Queue[int]) {
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			queue.Push(i)
+			_, _ = queue.Dequeue()
+			i++
+		}
+	})
+}
+
+func BenchmarkQueue(b *testing.B) {
+	benchmarkQueue(b, collection.NewQueue[int]())
+}
+
+func BenchmarkRingQueue(b *testing.B) {
+	benchmarkQueue(b, collection.MustNewRingQueue[int](benchQueueCap))
+}
+
+func BenchmarkQueueParallel(b *testing.B) {
+	benchmarkQueueParallel(b, collection.NewQueue[int]())
+}
+
+func BenchmarkRingQueueParallel(b *testing.B) {
+	benchmarkQueueParallel(b, collection.MustNewRingQueue[int](benchQueueCap))
+}
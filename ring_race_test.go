@@ -0,0 +1,152 @@
+package collection_test
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/trviph/collection"
+)
+
+func TestRingQueueRace(t *testing.T) {
+	var wg sync.WaitGroup
+	queue := collection.MustNewRingQueue[int](randint(1, 64))
+	functions := []func(){
+		// Push to the queue
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = queue.TryPush(rand.Int())
+			}
+		},
+
+		// Push to the queue
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = queue.TryPush(rand.Int(), rand.Int())
+			}
+		},
+
+		// Dequeue from the queue
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = queue.Dequeue()
+			}
+		},
+
+		// Dequeue from the queue
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = queue.Dequeue()
+			}
+		},
+
+		// Peek on the queue
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = queue.Front()
+			}
+		},
+
+		// Peek on the queue
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_, _ = queue.Rear()
+			}
+		},
+
+		// Check the length of the queue
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				if length := queue.Length(); length < 0 || length > queue.Cap() {
+					t.Errorf(testFailedMsg, "TestRingQueueRace", queue.Cap(), length)
+				}
+			}
+		},
+	}
+
+	wg.Add(len(functions))
+	for _, f := range functions {
+		go f()
+	}
+	wg.Wait()
+}
+
+func TestRingQueuePeekRace(t *testing.T) {
+	queue := collection.MustNewRingQueue[int](4)
+	total := 20000
+
+	var wg sync.WaitGroup
+	var done atomic.Bool
+	var dequeued atomic.Int64
+	functions := []func(){
+		// Push every value to the queue
+		func() {
+			defer wg.Done()
+			for i := range total {
+				queue.Push(i)
+			}
+		},
+
+		// Dequeue every value from the queue, in the order they are pushed
+		func() {
+			defer wg.Done()
+			defer done.Store(true)
+			for dequeued.Load() < int64(total) {
+				value, err := queue.Dequeue()
+				if err != nil {
+					runtime.Gosched()
+					continue
+				}
+				if int64(value) != dequeued.Load() {
+					t.Errorf(testFailedMsg, "TestRingQueuePeekRace", dequeued.Load(), value)
+				}
+				dequeued.Add(1)
+			}
+		},
+	}
+	// Peek on the queue until every value is dequeued, yielding now and then so the others can run on a single processor
+	for _, peek := range []func() (int, error){queue.Front, queue.Front, queue.Front, queue.Rear} {
+		functions = append(functions, func() {
+			defer wg.Done()
+			for i := 0; !done.Load(); i++ {
+				if value, err := peek(); err == nil && (value < 0 || value >= total) {
+					t.Errorf(testFailedMsg, "TestRingQueuePeekRace", "pushed value", value)
+				}
+				if i%64 == 0 {
+					runtime.Gosched()
+				}
+			}
+		})
+	}
+
+	finished := make(chan struct{})
+	wg.Add(len(functions))
+	for _, f := range functions {
+		go f()
+	}
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		// A value is lost or the queue is stuck
+		done.Store(true)
+		t.Fatalf(testFailedMsg, "TestRingQueuePeekRace", total, dequeued.Load())
+	}
+	if queue.Length() != 0 {
+		t.Errorf(testFailedMsg, "TestRingQueuePeekRace", 0, queue.Length())
+	}
+}
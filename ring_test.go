@@ -0,0 +1,160 @@
+package collection_test
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/trviph/collection"
+)
+
+func TestNewRingQueue(t *testing.T) {
+	if _, err := collection.NewRingQueue[int](0); err == nil {
+		t.Errorf(testFailedMsg, "TestNewRingQueue", "error", err)
+	}
+
+	// Should round the capacity up to a power of two
+	capacities := map[int]int{1: 1, 2: 2, 3: 4, 5: 8, 1000: 1024, 1024: 1024}
+	for capacity, want := range capacities {
+		queue := collection.MustNewRingQueue[int](capacity)
+		if queue.Cap() != want {
+			t.Errorf(testFailedMsg, "TestNewRingQueue", want, queue.Cap())
+		}
+	}
+}
+
+func TestRingQueue(t *testing.T) {
+	queue := collection.MustNewRingQueue[int](4)
+
+	if _, err := queue.Dequeue(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestRingQueue", collection.ErrIsEmpty, err)
+	}
+	if _, err := queue.Front(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestRingQueue", collection.ErrIsEmpty, err)
+	}
+	if _, err := queue.Rear(); !errors.Is(err, collection.ErrIsEmpty) {
+		t.Errorf(testFailedMsg, "TestRingQueue", collection.ErrIsEmpty, err)
+	}
+
+	queue.Push(1, 2, 3)
+	if queue.Length() != 3 {
+		t.Errorf(testFailedMsg, "TestRingQueue", 3, queue.Length())
+	}
+	if value, err := queue.Front(); err != nil || value != 1 {
+		t.Errorf(testFailedMsg, "TestRingQueue", 1, value)
+	}
+	if value, err := queue.Rear(); err != nil || value != 3 {
+		t.Errorf(testFailedMsg, "TestRingQueue", 3, value)
+	}
+
+	// Should push 4, then fail on 5 since the queue is full
+	if err := queue.TryPush(4, 5); !errors.Is(err, collection.ErrFull) {
+		t.Errorf(testFailedMsg, "TestRingQueue", collection.ErrFull, err)
+	}
+	if value, err := queue.Rear(); err != nil || value != 4 {
+		t.Errorf(testFailedMsg, "TestRingQueue", 4, value)
+	}
+
+	// Should go around the ring many times in order
+	for i := 1; i <= 100; i++ {
+		value, err := queue.Dequeue()
+		if err != nil || value != i {
+			t.Fatalf(testFailedMsg, "TestRingQueue", i, value)
+		}
+		if err := queue.TryPush(i + 4); err != nil {
+			t.Fatalf(testFailedMsg, "TestRingQueue", "nil error", err)
+		}
+		if value, err := queue.Front(); err != nil || value != i+1 {
+			t.Fatalf(testFailedMsg, "TestRingQueue", i+1, value)
+		}
+		if value, err := queue.Rear(); err != nil || value != i+4 {
+			t.Fatalf(testFailedMsg, "TestRingQueue", i+4, value)
+		}
+	}
+	if queue.Length() != 4 {
+		t.Errorf(testFailedMsg, "TestRingQueue", 4, queue.Length())
+	}
+}
+
+func TestRingQueuePushWait(t *testing.T) {
+	queue := collection.MustNewRingQueue[int](1)
+	queue.Push(1)
+
+	// Should wait until 1 is dequeued
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		queue.Push(2)
+	}()
+	if value, err := queue.Dequeue(); err != nil || value != 1 {
+		t.Errorf(testFailedMsg, "TestRingQueuePushWait", 1, value)
+	}
+	<-done
+	if value, err := queue.Dequeue(); err != nil || value != 2 {
+		t.Errorf(testFailedMsg, "TestRingQueuePushWait", 2, value)
+	}
+}
+
+func TestRingQueueMPMC(t *testing.T) {
+	queue := collection.MustNewRingQueue[int](8)
+	producers, consumers, perProducer := 4, 4, 1000
+
+	var wg sync.WaitGroup
+	results := make(chan []int, consumers)
+	remaining := make(chan struct{}, producers*perProducer)
+	for range producers * perProducer {
+		remaining <- struct{}{}
+	}
+	close(remaining)
+	for p := range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perProducer {
+				queue.Push(p*perProducer + i)
+			}
+		}()
+	}
+	for range consumers {
+		go func() {
+			got := make([]int, 0)
+			last := make(map[int]int)
+			for range remaining {
+				for {
+					value, err := queue.Dequeue()
+					if err != nil {
+						runtime.Gosched()
+						continue
+					}
+					// Values of the same producer come out in order
+					producer := value / perProducer
+					if prev, ok := last[producer]; ok && prev >= value {
+						t.Errorf(testFailedMsg, "TestRingQueueMPMC", "increasing values", value)
+					}
+					last[producer] = value
+					got = append(got, value)
+					break
+				}
+			}
+			results <- got
+		}()
+	}
+	wg.Wait()
+
+	// Should dequeue every value exactly once
+	all := make([]int, 0, producers*perProducer)
+	for range consumers {
+		all = append(all, <-results...)
+	}
+	slices.Sort(all)
+	for i, value := range all {
+		if value != i {
+			t.Fatalf(testFailedMsg, "TestRingQueueMPMC", i, value)
+		}
+	}
+	if len(all) != producers*perProducer {
+		t.Errorf(testFailedMsg, "TestRingQueueMPMC", producers*perProducer, len(all))
+	}
+}
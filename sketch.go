@@ -0,0 +1,117 @@
+package collection
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/trviph/collection/internal"
+)
+
+// A [CountMinSketch] is a probabilistic data structure estimating how many times a hash has been added,
+// using a fixed amount of memory. The estimate is never less than the real count,
+// but may be greater because of collisions between hashes.
+// All operation on [CountMinSketch] is thread-safe,
+// because it only allow one goroutine at a time to access it data.
+//
+// Each counter saturates at 255, and [CountMinSketch.Halve] can be called periodically
+// to age the counts so the sketch favors recent additions.
+//
+// [Count–min sketch]: https://en.wikipedia.org/wiki/Count%E2%80%93min_sketch
+type CountMinSketch struct {
+	mu sync.RWMutex
+	// Each row holds width counters, width is a power of two.
+	rows [][]uint8
+	mask uint64
+}
+
+// Interface guard
+var _ internal.CountMinSketch = (*CountMinSketch)(nil)
+
+// [NewCountMinSketch] creates a new [CountMinSketch] with depth rows of width counters each.
+// The width is rounded up to a power of two, more counters per row means less collisions,
+// and more rows means a collision is less likely to affect the estimate.
+// This will return an error if width or depth is less than 1.
+func NewCountMinSketch(width, depth int) (*CountMinSketch, error) {
+	if width < 1 || depth < 1 {
+		return nil, fmt.Errorf("failed to create count-min sketch; cause by invalid specified width of %d or depth of %d", width, depth)
+	}
+
+	width = 1 << bits.Len(uint(width-1))
+	rows := make([][]uint8, depth)
+	for i := range rows {
+		rows[i] = make([]uint8, width)
+	}
+	return &CountMinSketch{rows: rows, mask: uint64(width - 1)}, nil
+}
+
+// Like [NewCountMinSketch] but will panic on error.
+func MustNewCountMinSketch(width, depth int) *CountMinSketch {
+	return Must(func() (*CountMinSketch, error) {
+		return NewCountMinSketch(width, depth)
+	})
+}
+
+// Add increases the count of a hash by one.
+func (s *CountMinSketch) Add(hash uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, row := range s.rows {
+		idx := s.index(hash, i)
+		if row[idx] < 255 {
+			row[idx]++
+		}
+	}
+}
+
+// Estimate returns the estimated number of times the hash has been added.
+func (s *CountMinSketch) Estimate(hash uint64) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	estimate := 255
+	for i, row := range s.rows {
+		estimate = min(estimate, int(row[s.index(hash, i)]))
+	}
+	return estimate
+}
+
+// Halve divides all counts by two, so older additions weigh less than newer ones.
+func (s *CountMinSketch) Halve() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range s.rows {
+		for i := range row {
+			row[i] >>= 1
+		}
+	}
+}
+
+// Reset sets all counts back to zero.
+func (s *CountMinSketch) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range s.rows {
+		clear(row)
+	}
+}
+
+// Get the index of the counter of a hash in a row, by using double hashing
+// to derive an independent hash for each row from a single hash.
+func (s *CountMinSketch) index(hash uint64, row int) uint64 {
+	return (hash + uint64(row)*rehash(hash)) & s.mask
+}
+
+// Derive a second hash from a hash, by the finalizer of SplitMix64.
+func rehash(hash uint64) uint64 {
+	hash ^= hash >> 30
+	hash *= 0xbf58476d1ce4e5b9
+	hash ^= hash >> 27
+	hash *= 0x94d049bb133111eb
+	hash ^= hash >> 31
+	// Make it odd, so every counter of a row can be reached
+	return hash | 1
+}
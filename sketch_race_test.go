@@ -0,0 +1,53 @@
+package collection_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/trviph/collection"
+)
+
+func TestCountMinSketchRace(t *testing.T) {
+	var wg sync.WaitGroup
+	sketch := collection.MustNewCountMinSketch(randint(10, 1000), randint(1, 8))
+	functions := []func(){
+		// Add to the sketch
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				sketch.Add(rand.Uint64())
+			}
+		},
+
+		// Estimate from the sketch
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 1000); i++ {
+				_ = sketch.Estimate(rand.Uint64())
+			}
+		},
+
+		// Age the sketch
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 100); i++ {
+				sketch.Halve()
+			}
+		},
+
+		// Reset the sketch
+		func() {
+			defer wg.Done()
+			for i := 0; i < randint(10, 100); i++ {
+				sketch.Reset()
+			}
+		},
+	}
+
+	wg.Add(len(functions))
+	for _, f := range functions {
+		go f()
+	}
+	wg.Wait()
+}
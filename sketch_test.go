@@ -0,0 +1,77 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/trviph/collection"
+)
+
+func TestNewCountMinSketch(t *testing.T) {
+	_, err := collection.NewCountMinSketch(0, 4)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewCountMinSketch", "error", err)
+	}
+	_, err = collection.NewCountMinSketch(16, 0)
+	if err == nil {
+		t.Errorf(testFailedMsg, "TestNewCountMinSketch", "error", err)
+	}
+	_, err = collection.NewCountMinSketch(16, 4)
+	if err != nil {
+		t.Errorf(testFailedMsg, "TestNewCountMinSketch", "nil error", err)
+	}
+}
+
+func TestMustNewCountMinSketch(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf(testFailedMsg, "TestMustNewCountMinSketch", "panic", r)
+		}
+	}()
+	_ = collection.MustNewCountMinSketch(-1, 4)
+}
+
+func TestCountMinSketch(t *testing.T) {
+	sketch := collection.MustNewCountMinSketch(1024, 4)
+
+	// Hash i is added i times
+	for hash := uint64(0); hash < 100; hash++ {
+		for i := uint64(0); i < hash; i++ {
+			sketch.Add(hash * 0x9e3779b97f4a7c15)
+		}
+	}
+
+	// The estimate is never less than the real count,
+	// and with this few hashes should be exact for most of them.
+	exact := 0
+	for hash := uint64(0); hash < 100; hash++ {
+		got := sketch.Estimate(hash * 0x9e3779b97f4a7c15)
+		if got < int(hash) {
+			t.Errorf(testFailedMsg, "TestCountMinSketch", hash, got)
+		}
+		if got == int(hash) {
+			exact++
+		}
+	}
+	if exact < 90 {
+		t.Errorf(testFailedMsg, "TestCountMinSketch", "at least 90 exact estimates", exact)
+	}
+
+	// Counters saturate instead of overflowing
+	for i := 0; i < 1000; i++ {
+		sketch.Add(42)
+	}
+	if got := sketch.Estimate(42); got != 255 {
+		t.Errorf(testFailedMsg, "TestCountMinSketch", 255, got)
+	}
+
+	// Halving ages the counts
+	sketch.Halve()
+	if got := sketch.Estimate(42); got != 127 {
+		t.Errorf(testFailedMsg, "TestCountMinSketch", 127, got)
+	}
+
+	sketch.Reset()
+	if got := sketch.Estimate(42); got != 0 {
+		t.Errorf(testFailedMsg, "TestCountMinSketch", 0, got)
+	}
+}